@@ -1,15 +1,15 @@
 import { SERVER_URL } from "../constants";
 import type { TArticle } from "../types/articles";
+import { timeZone } from "../utils/timeZone";
 
 class ArticleService {
   getAll = async ({
     limit,
-    articleIDCursor,
-    dateCursor,
-    offset,
+    cursor = "",
+    dateCursor = "",
   }: TArticle["getAllArticles"]) => {
     const response = await fetch(
-      `${SERVER_URL}/api/v0.1/articles?limit=${limit}&articleIDCursor=${articleIDCursor}&dateCursor=${dateCursor}&offset=${offset}`,
+      `${SERVER_URL}/api/v0.1/articles?limit=${limit}&cursor=${cursor}&dateCursor=${dateCursor}`,
       {
         method: "GET",
         headers: {
@@ -28,12 +28,29 @@ class ArticleService {
   search = async ({
     query,
     limit,
-    articleIDCursor,
-    dateCursor,
-    offset,
+    cursor = "",
   }: TArticle["searchArticles"]) => {
     const response = await fetch(
-      `${SERVER_URL}/api/v0.1/articles/search?limit=${limit}&query=${query}&articleIDCursor=${articleIDCursor}&dateCursor=${dateCursor}&offset=${offset}`,
+      `${SERVER_URL}/api/v0.1/articles/search?limit=${limit}&query=${encodeURIComponent(query)}&cursor=${cursor}`,
+      {
+        method: "GET",
+        headers: {
+          "Content-type": "application/json",
+        },
+      }
+    );
+
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.message);
+    }
+
+    return await response.json();
+  };
+
+  suggest = async ({ q }: TArticle["suggestArticles"]) => {
+    const response = await fetch(
+      `${SERVER_URL}/api/v0.1/articles/suggest?q=${encodeURIComponent(q)}`,
       {
         method: "GET",
         headers: {
@@ -52,7 +69,7 @@ class ArticleService {
 
   getDayCount = async ({ limit, dateCursor }: TArticle["getAllArticles"]) => {
     const response = await fetch(
-      `${SERVER_URL}/api/v0.1/articles/day-count?limit=${limit}&dateCursor=${dateCursor}`,
+      `${SERVER_URL}/api/v0.1/articles/day-count?limit=${limit}&dateCursor=${dateCursor}&tz=${encodeURIComponent(timeZone)}`,
       {
         method: "GET",
         headers: {
@@ -69,12 +86,15 @@ class ArticleService {
   };
 
   getByDay = async ({ day }: TArticle["getByDay"]) => {
-    const response = await fetch(`${SERVER_URL}/api/v0.1/articles/day/${day}`, {
-      method: "GET",
-      headers: {
-        "Content-type": "application/json",
-      },
-    });
+    const response = await fetch(
+      `${SERVER_URL}/api/v0.1/articles/day/${day}?tz=${encodeURIComponent(timeZone)}`,
+      {
+        method: "GET",
+        headers: {
+          "Content-type": "application/json",
+        },
+      }
+    );
 
     if (!response.ok) {
       const error = await response.json();
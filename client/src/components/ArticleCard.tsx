@@ -24,6 +24,11 @@ export const ArticleCard: React.FC<ArticleCardProp> = (props) => {
             alt={props.article.title}
             className="w-full h-52 object-cover object-center rounded-lg mx-auto
             bg-(--clr-background)"
+            width={props.article.imageWidth}
+            height={props.article.imageHeight}
+            style={{
+              backgroundColor: props.article.imageDominantColor,
+            }}
             loading="lazy"
             decoding="async"
           />
@@ -8,16 +8,20 @@ import { isArrayWithElements } from "../utils/isArrayWithElements";
 
 interface ArticlesPerDayProps {
   day: string;
+  // The day's count from the day-count rollup, days without articles
+  // are not fetched
+  count: number;
 }
 
 const ArticlesPerDay: React.FC<ArticlesPerDayProps> = (props) => {
-  const { isPending, data } = useQuery({
+  const { isLoading, data } = useQuery({
     queryKey: [`articles-${props.day}`],
     queryFn: () => {
       return article.getByDay({
         day: props.day,
       });
     },
+    enabled: props.count > 0,
   });
 
   const articles: TArticle["article"][] = data?.data ?? [];
@@ -31,7 +35,7 @@ const ArticlesPerDay: React.FC<ArticlesPerDayProps> = (props) => {
     });
   };
 
-  if (isPending) {
+  if (isLoading) {
     return (
       <div
         className="text-gray-50 flex-1 w-full h-full flex 
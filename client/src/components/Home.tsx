@@ -37,19 +37,17 @@ export const Home: React.FC = () => {
 
   const [searchParams, setSearchParams] = useSearchParams();
   const [loader, setLoader] = useState<Loader>("INITIAL");
-  const articleIDCursor = searchParams.get("aIDCursor");
+  const articleIDCursor = searchParams.get("cursor");
   const dateCursor = searchParams.get("dCursor");
-  const offset = searchParams.get("offset");
   const searchQuery = searchParams.get("query");
 
   const { isPending, data } = useQuery({
-    queryKey: [`articles-${articleIDCursor}-${dateCursor}-${offset}`],
+    queryKey: [`articles-${articleIDCursor}-${dateCursor}`],
     queryFn: () => {
       return article.getAll({
         limit: 18,
-        articleIDCursor: !!articleIDCursor ? articleIDCursor : "",
+        cursor: !!articleIDCursor ? articleIDCursor : "",
         dateCursor: !!dateCursor ? dateCursor : "",
-        offset: "",
       });
     },
   });
@@ -72,7 +70,7 @@ export const Home: React.FC = () => {
               "dCursor",
               new Date(values.timeTravelBitcoin).toISOString()
             );
-            prev.set("aIDCursor", "");
+            prev.set("cursor", "");
             prev.set("query", "");
             return prev;
           },
@@ -94,7 +92,7 @@ export const Home: React.FC = () => {
     setSearchParams(
       (prev) => {
         prev.set("dCursor", "");
-        prev.set("aIDCursor", pagination!.prevCursor);
+        prev.set("cursor", pagination!.nextCursor);
         prev.set("query", "");
         return prev;
       },
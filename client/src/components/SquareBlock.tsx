@@ -58,7 +58,10 @@ export const SquareBlock: React.FC<SquareBlockProps> = (props) => {
         >
           {`${getDate(props.articleCount.date)}`}
         </p>
-        <ArticlesPerDay day={dateToIsoString(props.articleCount.date)} />
+        <ArticlesPerDay
+          day={dateToIsoString(props.articleCount.date)}
+          count={props.articleCount.count}
+        />
       </div>
     </Modal>
   );
@@ -16,8 +16,19 @@ type Article = {
   href?: string;
   imageUrl: string;
   imageFilename: string;
+  imageWidth?: number;
+  imageHeight?: number;
+  imageAspectRatio?: number;
+  imageDominantColor?: string;
+  imageBlurHash?: string;
   postedAt: string;
   readDuration: string;
+  readDurationMinutes: number;
+  sourceTag?: string;
+  clickCount: number;
+  body?: string;
+  searchRank?: number;
+  searchHighlight?: string;
   createdAt: string;
   updatedAt: string;
   author: Prettify<Author>;
@@ -25,9 +36,8 @@ type Article = {
 
 type GetAllArticles = {
   limit: number;
-  articleIDCursor?: string;
+  cursor?: string;
   dateCursor?: string;
-  offset?: string;
 };
 
 type GetByDay = {
@@ -37,9 +47,19 @@ type GetByDay = {
 type SearchArticles = {
   limit: number;
   query: string;
-  articleIDCursor?: string;
-  dateCursor?: string;
-  offset?: number;
+  cursor?: string;
+};
+
+type SuggestArticles = {
+  q: string;
+};
+
+type Suggestion = {
+  type: "tagIndex" | "author" | "tag" | "title";
+  text: string;
+  articleID?: string;
+  authorID?: string;
+  tagIndex?: string;
 };
 
 type CountArticle = {
@@ -53,5 +73,7 @@ export type TArticle = {
   getAllArticles: Prettify<GetAllArticles>;
   getByDay: Prettify<GetByDay>;
   searchArticles: Prettify<SearchArticles>;
+  suggestArticles: Prettify<SuggestArticles>;
+  suggestion: Prettify<Suggestion>;
   countArticle: Prettify<CountArticle>;
 };
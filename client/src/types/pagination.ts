@@ -1,6 +1,8 @@
 export type Pagination = {
   count: number;
   limit: number;
-  offset: number;
+  nextCursor: string;
   prevCursor: string;
+  next: string;
+  prev: string;
 };
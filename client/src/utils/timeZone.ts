@@ -0,0 +1,3 @@
+// The browser's IANA time zone e.g "Africa/Nairobi", sent as the tz
+// parameter so calendar days match the ones the user lives in
+export const timeZone = Intl.DateTimeFormat().resolvedOptions().timeZone;
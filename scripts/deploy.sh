@@ -70,6 +70,11 @@ echo "About to deploy stack with image: ${NEW_IMAGE}"
 # Export NEW_IMAGE as environment variable for docker-compose
 export NEW_IMAGE=$NEW_IMAGE
 
+# Apply database migrations before the new image starts serving,
+# the server refuses to start while migrations are pending
+echo "Running database migrations..."
+docker run --rm --env-file ./.env "$NEW_IMAGE" migrate up
+
 # Deploy or update the stack
 if docker stack ls | grep -q "app-stack"; then
   echo "Updating existing stack..."
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/articles"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/postgres"
+)
+
+// runBackfillImageMetadata fills in the image dimensions, dominant
+// colour and blurhash of articles saved before image metadata existed
+func runBackfillImageMetadata() {
+	checkSchema()
+
+	start := time.Now()
+	handler := articles.NewHandler(postgres.NewRepositories(models.Db()))
+	handler.UpdateArticleImageMetadata()
+	invalidateResponses()
+
+	log.Printf("Backfilled image metadata in %s", time.Since(start))
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/export"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/postgres"
+)
+
+// runExport writes the articles to a file like GET /api/v0.1/export,
+// e.g "export -format parquet -output articles.parquet -tag #bitcoin"
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", export.FormatCSV, "one of "+strings.Join(export.Formats, ", "))
+	output := flags.String("output", "", "file to write, articles.<format> by default")
+	compress := flags.Bool("gzip", false, "gzip the file")
+
+	var filter repository.ArticleFilter
+	flags.StringVar(&filter.AuthorID, "authorID", "", "only articles of the author with this id")
+	flags.StringVar(&filter.AuthorName, "author", "", "only articles of the author with this name")
+	flags.StringVar(&filter.Tag, "tag", "", "only articles with this tag")
+	flags.StringVar(&filter.SourceTag, "sourceTag", "", "only articles scraped from this tag")
+	from := flags.String("from", "", "only articles posted from this date, YYYY-MM-DD")
+	to := flags.String("to", "", "only articles posted up to this date, YYYY-MM-DD")
+	flags.Parse(args)
+
+	if *output == "" {
+		*output = "articles." + *format
+		if *compress {
+			*output += ".gz"
+		}
+	}
+	if *from != "" {
+		date, err := time.Parse(time.DateOnly, *from)
+		if err != nil {
+			log.Fatalf("Invalid from date: %s", *from)
+		}
+		filter.PostedFrom = date
+	}
+	if *to != "" {
+		date, err := time.Parse(time.DateOnly, *to)
+		if err != nil {
+			log.Fatalf("Invalid to date: %s", *to)
+		}
+		filter.PostedTo = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	checkSchema()
+
+	file, err := os.Create(*output)
+	if err != nil {
+		log.Fatal("Failed to create the export file: ", err)
+	}
+	defer file.Close()
+
+	buffer := bufio.NewWriter(file)
+	writer, err := export.NewWriter(buffer, *format, *compress)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var count int
+	articles := postgres.NewArticleRepository(models.Db())
+	err = articles.Stream(filter, constants.EXPORT_BATCH_SIZE, func(batch []models.Article) error {
+		count += len(batch)
+		return writer.Write(batch)
+	})
+	if err != nil {
+		log.Fatal("Failed to export articles: ", err)
+	}
+	if err := writer.Close(); err != nil {
+		log.Fatal("Failed to finish the export: ", err)
+	}
+	if err := buffer.Flush(); err != nil {
+		log.Fatal("Failed to write the export file: ", err)
+	}
+
+	log.Printf("Exported %d articles to %s", count, *output)
+}
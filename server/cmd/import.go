@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/cache"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/articles"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/middlewares"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/postgres"
+)
+
+// runImport applies a ScrapedData file or url to the saved articles,
+// e.g "import -source 20250810-hn-bitcoin-articles.json -mode links
+// -progress links.progress -resume". The summary report is printed as
+// JSON or written to -report
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+
+	var options articles.ImportOptions
+	flags.StringVar(&options.Source, "source", "", "path or http(s) url of the ScrapedData file")
+	flags.StringVar(&options.Mode, "mode", articles.ImportDiff, "one of "+strings.Join(articles.ImportModes, ", "))
+	flags.BoolVar(&options.DryRun, "dry-run", false, "report what would change without writing anything")
+	flags.StringVar(&options.ProgressFile, "progress", "", "file recording the processed articles")
+	flags.BoolVar(&options.Resume, "resume", false, "skip the articles already in the progress file")
+	reportFile := flags.String("report", "", "file to write the summary report to")
+	flags.Parse(args)
+
+	if options.Source == "" {
+		log.Fatal("import needs a -source file or url")
+	}
+
+	checkSchema()
+
+	handler := articles.NewHandler(postgres.NewRepositories(models.Db()))
+	report, err := handler.Import(options)
+	if err != nil {
+		log.Fatal("Failed to import articles: ", err)
+	}
+
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal("Failed to encode the import report: ", err)
+	}
+	if *reportFile == "" {
+		os.Stdout.Write(append(output, '\n'))
+	} else if err := os.WriteFile(*reportFile, output, 0o644); err != nil {
+		log.Fatal("Failed to write the import report: ", err)
+	}
+
+	if !options.DryRun {
+		invalidateResponses()
+	}
+
+	log.Printf("Imported %d articles from %s: %d created, %d updated, %d unchanged, %d skipped, %d resumed, %d failed, %d without an image",
+		report.Total, report.Source, report.Created, report.Updated, report.Unchanged, report.Skipped, report.Resumed, report.Failed,
+		report.WithoutImage)
+}
+
+// invalidateResponses drops the responses cached by the servers after a
+// command changed the articles. Without REDIS_URL the servers keep
+// their own in-memory caches, they have to be restarted instead
+func invalidateResponses() {
+	if os.Getenv("REDIS_URL") == "" {
+		log.Println("REDIS_URL is not set, restart servers using the in-memory cache to drop their cached responses")
+		return
+	}
+
+	sharedCache, err := cache.FromEnv()
+	if err != nil {
+		log.Fatal("Failed to connect to the cache: ", err)
+	}
+	defer sharedCache.Close()
+
+	if err := middlewares.InvalidateResponses(context.Background(), sharedCache); err != nil {
+		log.Fatal("Failed to invalidate the cached responses: ", err)
+	}
+	log.Println("Invalidated the cached responses")
+}
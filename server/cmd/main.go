@@ -5,13 +5,23 @@ import (
 	"log"
 	"os"
 
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/autocomplete"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/cache"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/events/subscribers"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/articles"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/authors"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/exports"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/feeds"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/health"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/status"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/suggestions"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/tags"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/uploads"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/middlewares"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/postgres"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -20,19 +30,65 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1:])
+		return
+	}
+
+	checkSchema()
+
+	repos := postgres.NewRepositories(models.Db())
+	articleHandler := articles.NewHandler(repos)
+	authorHandler := authors.NewHandler(repos)
+	tagHandler := tags.NewHandler(repos)
+	feedHandler := feeds.NewHandler(repos)
+	exportHandler := exports.NewHandler(repos)
+	// Shared by the replicas when REDIS_URL is set
+	sharedCache, err := cache.FromEnv()
+	if err != nil {
+		log.Fatalf("Error connecting to the cache: %v", err)
+	}
+	// The in-memory cache evicts entries once full, the rate limit and
+	// upload quota counters get their own so cached responses can't
+	// push them out
+	counterCache := sharedCache
+	if _, ok := sharedCache.(*cache.Memory); ok {
+		counterCache = cache.NewMemory()
+	}
+	rateLimiter := middlewares.NewRateLimiter(counterCache)
+	responseCache := middlewares.NewResponseCache(sharedCache)
+
+	uploadHandler := uploads.NewHandler(repos, counterCache)
+	go uploadHandler.ExpirePendingUploads()
+
+	suggestionIndex := autocomplete.NewIndex(repos)
+	suggestionIndex.Generation = responseCache.Generation
+	go suggestionIndex.Run()
+	suggestionHandler := suggestions.NewHandler(suggestionIndex)
+
+	// Bodies above BodyLimit are streamed to the handlers rather than
+	// refused, so uploads can go up to UPLOAD_MAX_REQUEST_SIZE while
+	// LimitRequestBody holds every other route to REQUEST_MAX_BODY_SIZE
 	app := fiber.New(fiber.Config{
-		ErrorHandler: pkg.DefaultErrorHandler,
+		ErrorHandler:                 pkg.DefaultErrorHandler,
+		BodyLimit:                    constants.REQUEST_MAX_BODY_SIZE,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:  "*",
-		AllowHeaders:  "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, X-API-Key",
 		ExposeHeaders: "Content-Length",
 	}))
 
 	app.Use(logger.New())
 
-	app.Use(middlewares.RateLimit)
+	app.Use(middlewares.LimitRequestBody(constants.REQUEST_MAX_BODY_SIZE, map[string]int{
+		"/api/v0.1/uploads": constants.UPLOAD_MAX_REQUEST_SIZE,
+	}))
+
+	app.Use(rateLimiter.Limit)
 
 	// Load dev .env file
 	env := os.Getenv("GO_ENV")
@@ -48,17 +104,46 @@ func main() {
 	userGroup := app.Group("/api/v0.1/articles", func(c *fiber.Ctx) error {
 		return c.Next()
 	})
-	userGroup.Get("/", articles.GetAllArticles)
-
-	userGroup.Get("/search", articles.SearchArticles)
-	userGroup.Get("/day-count", articles.GetArticleCountPerDay)
-	userGroup.Get("/day/:postedAt", articles.GetArticlesByDay)
+	userGroup.Get("/", responseCache.Handle, articleHandler.GetAllArticles)
+
+	userGroup.Get("/search", responseCache.Handle, articleHandler.SearchArticles)
+	userGroup.Get("/suggest", suggestionHandler.GetSuggestions)
+	userGroup.Get("/day-count", responseCache.Handle, articleHandler.GetArticleCountPerDay)
+	userGroup.Get("/day/:postedAt", responseCache.Handle, articleHandler.GetArticlesByDay)
+	userGroup.Get("/stats/timeseries", responseCache.Handle, articleHandler.GetArticleTimeseries)
+	userGroup.Get("/stats/insights", responseCache.Handle, articleHandler.GetArticleInsights)
+	// Registered last, ":id" also matches the static routes above
+	userGroup.Get("/:id", responseCache.Handle, articleHandler.GetArticle)
+
+	// Short links e.g /a/a1234, never cached as they count clicks
+	app.Get("/a/:tagIndex", articleHandler.RedirectShortLink)
+
+	// authors
+	authorGroup := app.Group("/api/v0.1/authors")
+	authorGroup.Get("/", responseCache.Handle, authorHandler.GetAllAuthors)
+	authorGroup.Get("/:id", responseCache.Handle, authorHandler.GetAuthor)
+	authorGroup.Get("/:id/articles", responseCache.Handle, authorHandler.GetAuthorArticles)
+
+	// tags
+	tagGroup := app.Group("/api/v0.1/tags")
+	tagGroup.Get("/", responseCache.Handle, tagHandler.GetAllTags)
+	tagGroup.Get("/:slug/articles", responseCache.Handle, tagHandler.GetTagArticles)
+
+	// feeds, filtered like the article listing. Their links and the
+	// pagination links use PUBLIC_BASE_URL, without it they are built
+	// from the Host and never cached
+	app.Get("/feeds/articles.rss", responseCache.Handle, feedHandler.GetRSSFeed)
+	app.Get("/feeds/articles.atom", responseCache.Handle, feedHandler.GetAtomFeed)
+	app.Get("/feeds/articles.json", responseCache.Handle, feedHandler.GetJSONFeed)
+
+	// export
+	app.Get("/api/v0.1/export", exportHandler.ExportArticles)
 
 	// uploads
-	uploadGroup := app.Group("/api/v0.1/uploads", func(c *fiber.Ctx) error {
-		return c.Next()
-	})
-	uploadGroup.Post("/", uploads.UploadFiles)
+	uploadGroup := app.Group("/api/v0.1/uploads", middlewares.RequireAPIKey)
+	uploadGroup.Post("/", uploadHandler.UploadFiles)
+	uploadGroup.Post("/presign", uploadHandler.PresignUpload)
+	uploadGroup.Post("/complete", uploadHandler.CompleteUpload)
 
 	// Status
 	app.Get("/status", status.GetAppStatus)
@@ -72,7 +157,33 @@ func main() {
 	})
 
 	// Initialize all event subscribers in the app
-	subscribers.InitEventSubscribers()
+	subscribers.InitEventSubscribers(repos)
+	responseCache.InvalidateOnArticleSaved()
+
+	// Replicas share the day's scrape through the cache lock
+	if os.Getenv("SCRAPE_SCHEDULE") == "true" {
+		go articles.ScheduleDailyScrape(sharedCache)
+	}
 
 	log.Fatal(app.Listen("0.0.0.0:3000"))
 }
+
+// runCommand dispatches the CLI sub commands e.g "migrate up"
+func runCommand(args []string) {
+	switch args[0] {
+	case "migrate":
+		runMigrate(args[1:])
+	case "export":
+		runExport(args[1:])
+	case "import":
+		runImport(args[1:])
+	case "snapshot":
+		runSnapshot(args[1:])
+	case "rebuild-day-counts":
+		runRebuildDayCounts(args[1:])
+	case "backfill-image-metadata":
+		runBackfillImageMetadata()
+	default:
+		log.Fatalf("Unknown command: %s", args[0])
+	}
+}
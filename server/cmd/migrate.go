@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/migrations"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+)
+
+func newMigrator() *migrations.Migrator {
+	sqlDB, err := models.Db().DB()
+	if err != nil {
+		log.Fatal("Failed to get database handle: ", err)
+	}
+
+	migrator, err := migrations.NewMigrator(sqlDB)
+	if err != nil {
+		log.Fatal("Failed to load migrations: ", err)
+	}
+	return migrator
+}
+
+// runMigrate handles "migrate up [n]", "migrate down [n]" and "migrate status"
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage: migrate up [steps] | down [steps] | status")
+	}
+
+	var steps int
+	if len(args) > 1 {
+		var err error
+		steps, err = strconv.Atoi(args[1])
+		if err != nil || steps < 0 {
+			log.Fatalf("Invalid number of steps: %s", args[1])
+		}
+	}
+
+	ctx := context.Background()
+	migrator := newMigrator()
+
+	switch args[0] {
+	case "up":
+		ran, err := migrator.Up(ctx, steps)
+		for _, migration := range ran {
+			log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		if len(ran) == 0 {
+			log.Println("Database schema is up to date")
+		}
+	case "down":
+		ran, err := migrator.Down(ctx, steps)
+		for _, migration := range ran {
+			log.Printf("Reverted migration %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		if len(ran) == 0 {
+			log.Println("No migration to revert")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("Failed to get migration status: ", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		log.Fatalf("Unknown migrate command: %s", args[0])
+	}
+}
+
+// checkSchema refuses to start the server while migrations are pending
+func checkSchema() {
+	if err := newMigrator().CheckCurrent(context.Background()); err != nil {
+		log.Fatalf("%v. Run \"migrate up\" before starting the server", err)
+	}
+	log.Println("Database schema is up to date")
+}
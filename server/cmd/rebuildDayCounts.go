@@ -0,0 +1,33 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/postgres"
+)
+
+// runRebuildDayCounts recounts the daily_article_counts rollup from
+// the articles table, e.g after articles were bulk loaded with the
+// triggers disabled. Zones given as arguments e.g
+// "rebuild-day-counts Africa/Kampala" are added to the rollup, it
+// only covers UTC otherwise
+func runRebuildDayCounts(args []string) {
+	checkSchema()
+
+	zones := make([]string, len(args))
+	for i, arg := range args {
+		location, err := time.LoadLocation(arg)
+		if err != nil {
+			log.Fatalf("Invalid time zone %q: %v", arg, err)
+		}
+		zones[i] = location.String()
+	}
+
+	articles := postgres.NewArticleRepository(models.Db())
+	if err := articles.RebuildDayCounts(zones); err != nil {
+		log.Fatal("Failed to rebuild day counts: ", err)
+	}
+	log.Println("Rebuilt day counts")
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/migrations"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/postgres"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/snapshot"
+)
+
+// runSnapshot handles "snapshot create" and "snapshot restore", e.g
+// "snapshot create -blobs -output prod.tar.gz" then
+// "snapshot restore -blobs -input prod.tar.gz" against a dev database
+// and bucket. Restore migrates the database first so it can start
+// from an empty one
+func runSnapshot(args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage: snapshot create [-output file] [-blobs] | restore -input file [-blobs]")
+	}
+
+	switch args[0] {
+	case "create":
+		runSnapshotCreate(args[1:])
+	case "restore":
+		runSnapshotRestore(args[1:])
+	default:
+		log.Fatalf("Unknown snapshot command: %s", args[0])
+	}
+}
+
+func runSnapshotCreate(args []string) {
+	flags := flag.NewFlagSet("snapshot create", flag.ExitOnError)
+	output := flags.String("output", "", "file to write, snapshot-<timestamp>.tar.gz by default")
+	withBlobs := flags.Bool("blobs", false, "include the bucket objects the rows point at")
+	flags.Parse(args)
+
+	if *output == "" {
+		*output = "snapshot-" + time.Now().UTC().Format("20060102-150405") + ".tar.gz"
+	}
+
+	checkSchema()
+
+	ctx := context.Background()
+	blobs := newSnapshotBlobStore(ctx, *withBlobs)
+
+	file, err := os.Create(*output)
+	if err != nil {
+		log.Fatal("Failed to create the snapshot file: ", err)
+	}
+	defer file.Close()
+
+	buffer := bufio.NewWriter(file)
+	snapshots := postgres.NewSnapshotRepository(models.Db())
+	summary, err := snapshot.Create(ctx, buffer, snapshots, blobs, latestSchemaVersion())
+	if err != nil {
+		log.Fatal("Failed to create the snapshot: ", err)
+	}
+	if err := buffer.Flush(); err != nil {
+		log.Fatal("Failed to write the snapshot file: ", err)
+	}
+
+	logSnapshotSummary("Created snapshot "+*output, summary)
+}
+
+func runSnapshotRestore(args []string) {
+	flags := flag.NewFlagSet("snapshot restore", flag.ExitOnError)
+	input := flags.String("input", "", "snapshot file to restore")
+	withBlobs := flags.Bool("blobs", false, "upload the archived blobs to the bucket and point the rows at it")
+	flags.Parse(args)
+
+	if *input == "" {
+		log.Fatal("snapshot restore needs an -input file")
+	}
+
+	ctx := context.Background()
+	ran, err := newMigrator().Up(ctx, 0)
+	for _, migration := range ran {
+		log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+	}
+	if err != nil {
+		log.Fatal("Migration failed: ", err)
+	}
+
+	blobs := newSnapshotBlobStore(ctx, *withBlobs)
+
+	file, err := os.Open(*input)
+	if err != nil {
+		log.Fatal("Failed to open the snapshot file: ", err)
+	}
+	defer file.Close()
+
+	snapshots := postgres.NewSnapshotRepository(models.Db())
+	manifest, summary, err := snapshot.Restore(ctx, file, snapshots, blobs, latestSchemaVersion())
+	if err != nil {
+		log.Fatal("Failed to restore the snapshot: ", err)
+	}
+	invalidateResponses()
+
+	logSnapshotSummary("Restored snapshot of "+manifest.CreatedAt.Format(time.RFC3339), summary)
+}
+
+// newSnapshotBlobStore returns the bucket when blobs are wanted, a
+// nil store makes the snapshot skip them
+func newSnapshotBlobStore(ctx context.Context, withBlobs bool) snapshot.BlobStore {
+	if !withBlobs {
+		return nil
+	}
+	s3Client, err := (&pkg.S3Client{}).NewS3Client(ctx)
+	if err != nil {
+		log.Fatal("Failed to create the s3 client: ", err)
+	}
+	return s3Client
+}
+
+func latestSchemaVersion() int {
+	loaded, err := migrations.Load()
+	if err != nil {
+		log.Fatal("Failed to load migrations: ", err)
+	}
+	if len(loaded) == 0 {
+		return 0
+	}
+	return loaded[len(loaded)-1].Version
+}
+
+func logSnapshotSummary(message string, summary snapshot.Summary) {
+	counts, err := json.Marshal(summary)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s: %s", message, counts)
+}
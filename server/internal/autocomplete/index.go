@@ -0,0 +1,266 @@
+// Package autocomplete answers search-as-you-type queries from an
+// in-memory trie of article titles, author names, tags and tag
+// indexes. The trie is rebuilt in the background whenever articles
+// are ingested, here or by another process
+package autocomplete
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/events"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+const (
+	TypeTagIndex = "tagIndex"
+	TypeAuthor   = "author"
+	TypeTag      = "tag"
+	TypeTitle    = "title"
+)
+
+// Per type caps applied before titles fill the remaining slots, so a
+// popular author can't crowd every title out
+var typeLimits = map[string]int{
+	TypeTagIndex: 3,
+	TypeAuthor:   3,
+	TypeTag:      3,
+}
+
+type Suggestion struct {
+	Type      string `json:"type"`
+	Text      string `json:"text"`
+	ArticleID string `json:"articleID,omitempty"`
+	AuthorID  string `json:"authorID,omitempty"`
+	TagIndex  string `json:"tagIndex,omitempty"`
+}
+
+type Index struct {
+	repos        repository.Repositories
+	trie         atomic.Pointer[Trie]
+	RebuildDelay time.Duration
+	// Generation changes whenever articles change in any process, e.g
+	// the response cache generation other replicas and CLI imports
+	// bump. It's polled every GenerationPollInterval when set
+	Generation             func(ctx context.Context) (string, error)
+	GenerationPollInterval time.Duration
+	// generation is the value Generation had when the trie was built
+	generation string
+}
+
+func NewIndex(repos repository.Repositories) *Index {
+	index := &Index{repos: repos, RebuildDelay: 5 * time.Second, GenerationPollInterval: 30 * time.Second}
+	index.trie.Store(newTrie())
+
+	return index
+}
+
+// Rebuild loads every article and author and swaps in a new trie,
+// lookups keep using the old one until it is ready
+func (i *Index) Rebuild() error {
+	startTime := time.Now()
+
+	// Read before loading, a change made meanwhile triggers another
+	// rebuild
+	if i.Generation != nil {
+		generation, err := i.Generation(context.Background())
+		if err != nil {
+			log.Println("Error reading the autocomplete index generation:", err)
+		}
+		i.generation = generation
+	}
+
+	// A negative limit loads every article
+	articles, _, err := i.repos.Articles.FindAllByPostedAtInAsc(-1)
+	if err != nil {
+		return err
+	}
+	authors, err := i.repos.Authors.FindAll(math.MaxInt32, "")
+	if err != nil {
+		return err
+	}
+
+	articleCountPerAuthor := make(map[string]int)
+	articleCountPerTag := make(map[string]int)
+	tagText := make(map[string]string)
+	for _, article := range articles {
+		articleCountPerAuthor[article.AuthorID]++
+
+		tag := normalize(article.Tag)
+		if tag != "" {
+			articleCountPerTag[tag]++
+			tagText[tag] = article.Tag
+		}
+	}
+
+	// Authors and tags score by their share of the articles of the
+	// busiest one, titles by recency, all between 0 and 1
+	maxPerAuthor, maxPerTag := 1, 1
+	for _, count := range articleCountPerAuthor {
+		maxPerAuthor = max(maxPerAuthor, count)
+	}
+	for _, count := range articleCountPerTag {
+		maxPerTag = max(maxPerTag, count)
+	}
+
+	trie := newTrie()
+
+	for index, article := range articles {
+		// Articles come oldest first, newer titles score higher
+		recency := float64(index+1) / float64(len(articles)+1)
+		trie.add(Suggestion{
+			Type:      TypeTitle,
+			Text:      article.Title,
+			ArticleID: article.ID,
+			TagIndex:  article.TagIndex,
+		}, recency)
+
+		if article.TagIndex != "" {
+			trie.add(Suggestion{
+				Type:      TypeTagIndex,
+				Text:      article.TagIndex,
+				ArticleID: article.ID,
+				TagIndex:  article.TagIndex,
+			}, recency)
+		}
+	}
+	for _, author := range authors {
+		trie.add(Suggestion{
+			Type:     TypeAuthor,
+			Text:     author.Name,
+			AuthorID: author.ID,
+		}, float64(articleCountPerAuthor[author.ID])/float64(maxPerAuthor))
+	}
+	for tag, count := range articleCountPerTag {
+		trie.add(Suggestion{Type: TypeTag, Text: tagText[tag]}, float64(count)/float64(maxPerTag))
+	}
+
+	i.trie.Store(trie)
+	log.Printf("Rebuilt autocomplete index with %d entries in %v", len(trie.entries), time.Since(startTime))
+
+	return nil
+}
+
+// Suggest returns at most limit suggestions for the query. Every
+// word but the last must match completely, the last one by prefix
+func (i *Index) Suggest(query string, limit int) []Suggestion {
+	suggestions := []Suggestion{}
+
+	normalized := normalize(query)
+	if normalized == "" || limit <= 0 {
+		return suggestions
+	}
+	words := strings.Fields(normalized)
+	lastWord := words[len(words)-1]
+
+	trie := i.trie.Load()
+
+	// Entries starting with the whole query come first, then those
+	// with a later word starting with the last query word
+	candidates := trie.find(normalized)
+	if len(words) > 1 {
+		candidates = append(candidates, trie.find(lastWord)...)
+	}
+
+	seen := make(map[Suggestion]bool)
+	var matches []entry
+	for _, candidate := range candidates {
+		if seen[candidate.suggestion] || !containsWords(candidate.normalized, words) {
+			continue
+		}
+		seen[candidate.suggestion] = true
+		matches = append(matches, candidate)
+	}
+
+	// Exact hits, e.g. a tag index typed in full, always lead
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].normalized == normalized && matches[j].normalized != normalized
+	})
+
+	countPerType := make(map[string]int)
+	var overflow []Suggestion
+	for _, match := range matches {
+		typeLimit, capped := typeLimits[match.suggestion.Type]
+		if capped && countPerType[match.suggestion.Type] >= typeLimit {
+			overflow = append(overflow, match.suggestion)
+			continue
+		}
+		countPerType[match.suggestion.Type]++
+		suggestions = append(suggestions, match.suggestion)
+	}
+	suggestions = append(suggestions, overflow...)
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// containsWords reports whether text has every word, the last one
+// as a prefix
+func containsWords(text string, words []string) bool {
+	textWords := strings.Fields(text)
+
+	for index, word := range words {
+		isLast := index == len(words)-1
+		found := false
+		for _, textWord := range textWords {
+			if textWord == word || (isLast && strings.HasPrefix(textWord, word)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Run builds the index then rebuilds it RebuildDelay after an
+// ARTICLE_SAVED event or a Generation change, batching the changes
+// seen meanwhile
+func (i *Index) Run() {
+	if err := i.Rebuild(); err != nil {
+		log.Println("Error building autocomplete index:", err)
+	}
+
+	articleSavedChan := make(chan events.DataEvent)
+	events.EB.Subscribe("ARTICLE_SAVED", articleSavedChan)
+
+	var poll <-chan time.Time
+	if i.Generation != nil {
+		ticker := time.NewTicker(i.GenerationPollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	var rebuild <-chan time.Time
+	for {
+		select {
+		case <-articleSavedChan:
+			if rebuild == nil {
+				rebuild = time.After(i.RebuildDelay)
+			}
+		case <-poll:
+			generation, err := i.Generation(context.Background())
+			if err != nil {
+				log.Println("Error reading the autocomplete index generation:", err)
+				continue
+			}
+			if generation != i.generation && rebuild == nil {
+				rebuild = time.After(i.RebuildDelay)
+			}
+		case <-rebuild:
+			rebuild = nil
+			if err := i.Rebuild(); err != nil {
+				log.Println("Error rebuilding autocomplete index:", err)
+			}
+		}
+	}
+}
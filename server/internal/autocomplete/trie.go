@@ -0,0 +1,134 @@
+package autocomplete
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// maxEntriesPerType bounds how many entries of each suggestion type
+// every trie node keeps. Types are ranked apart since their scores
+// aren't comparable, so many popular authors can't push every title
+// out of a short prefix. It leaves room for Suggest to drop entries
+// that don't contain every query word
+const maxEntriesPerType = 16
+
+type entry struct {
+	suggestion Suggestion
+	score      float64
+	normalized string
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	// entries holds indexes into Trie.entries per suggestion type,
+	// best score first
+	entries map[string][]int
+}
+
+// Trie maps key prefixes to the best scoring entries under them so
+// a lookup costs the length of the prefix, not the number of keys
+type Trie struct {
+	root    *trieNode
+	entries []entry
+}
+
+func newTrie() *Trie {
+	return &Trie{root: &trieNode{children: make(map[rune]*trieNode), entries: make(map[string][]int)}}
+}
+
+// normalize lowercases text and keeps only its letters and digits,
+// words separated by a single space
+func normalize(text string) string {
+	return strings.Join(repository.SearchWords(text), " ")
+}
+
+// add indexes the suggestion under its full text and under every
+// word of it, so "net" finds "Lightning Network Basics"
+func (t *Trie) add(suggestion Suggestion, score float64) {
+	normalized := normalize(suggestion.Text)
+	if normalized == "" {
+		return
+	}
+
+	index := len(t.entries)
+	t.entries = append(t.entries, entry{suggestion: suggestion, score: score, normalized: normalized})
+
+	keys := []string{normalized}
+	words := strings.Fields(normalized)
+	if len(words) > 1 {
+		keys = append(keys, words[1:]...)
+	}
+	for _, key := range keys {
+		t.insert(key, index)
+	}
+}
+
+func (t *Trie) insert(key string, index int) {
+	node := t.root
+	for _, r := range key {
+		child, ok := node.children[r]
+		if !ok {
+			child = &trieNode{children: make(map[rune]*trieNode), entries: make(map[string][]int)}
+			node.children[r] = child
+		}
+		node = child
+		suggestionType := t.entries[index].suggestion.Type
+		node.entries[suggestionType] = t.rank(node.entries[suggestionType], index)
+	}
+}
+
+// rank inserts index into the entries of one type keeping them
+// ordered by score and capped at maxEntriesPerType
+func (t *Trie) rank(entries []int, index int) []int {
+	for _, existing := range entries {
+		if existing == index {
+			return entries
+		}
+	}
+
+	position := sort.Search(len(entries), func(i int) bool {
+		return t.entries[entries[i]].score < t.entries[index].score
+	})
+	if position >= maxEntriesPerType {
+		return entries
+	}
+
+	entries = append(entries, 0)
+	copy(entries[position+1:], entries[position:])
+	entries[position] = index
+
+	if len(entries) > maxEntriesPerType {
+		entries = entries[:maxEntriesPerType]
+	}
+	return entries
+}
+
+// find returns the best entries of every type whose keys start with
+// prefix, best score first. Scores are normalized to at most 1 per
+// type, see Index.Rebuild
+func (t *Trie) find(prefix string) []entry {
+	node := t.root
+	for _, r := range prefix {
+		child, ok := node.children[r]
+		if !ok {
+			return nil
+		}
+		node = child
+	}
+
+	var entries []entry
+	for _, indexes := range node.entries {
+		for _, index := range indexes {
+			entries = append(entries, t.entries[index])
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].score != entries[j].score {
+			return entries[i].score > entries[j].score
+		}
+		return entries[i].normalized < entries[j].normalized
+	})
+	return entries
+}
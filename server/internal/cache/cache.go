@@ -0,0 +1,48 @@
+// Package cache holds the state replicas share: rate limit counters,
+// cached responses and the locks of scheduled jobs. The in-memory
+// backend only serves one process, the Redis backend is used once
+// REDIS_URL is set so any number of replicas agree
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+)
+
+// Cache stores byte values under string keys. A ttl of zero keeps the
+// value until it is deleted
+type Cache interface {
+	// Get reports false for a missing or expired key
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX sets the key only when it is missing and reports whether
+	// it did
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Incr adds one to the counter at key, a missing counter starts
+	// at one and expires after ttl
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// IncrBy adds n to the counter at key like Incr, n may be negative
+	IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error)
+	Delete(ctx context.Context, key string) error
+	// CompareAndDelete deletes the key only while it holds value and
+	// reports whether it did
+	CompareAndDelete(ctx context.Context, key string, value []byte) (bool, error)
+	Close() error
+}
+
+// ErrNotInteger is returned by Incr when the key holds another value
+var ErrNotInteger = errors.New("cached value is not an integer")
+
+// FromEnv connects to the Redis server in REDIS_URL, or returns an
+// in-memory cache when it isn't set
+func FromEnv() (Cache, error) {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		log.Println("REDIS_URL is not set, using the in-memory cache")
+		return NewMemory(), nil
+	}
+	return NewRedis(redisURL)
+}
@@ -0,0 +1,187 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/cache"
+	"github.com/alicebob/miniredis/v2"
+)
+
+// backend is a cache under test. advance moves its clock past ttls,
+// the memory cache really waits while miniredis fast forwards
+type backend struct {
+	newCache func(t *testing.T) cache.Cache
+	advance  func(d time.Duration)
+}
+
+func TestMemory(t *testing.T) {
+	runSuite(t, backend{
+		newCache: func(t *testing.T) cache.Cache {
+			return cache.NewMemory()
+		},
+		advance: time.Sleep,
+	})
+}
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	runSuite(t, backend{
+		newCache: func(t *testing.T) cache.Cache {
+			server.FlushAll()
+			c, err := cache.NewRedis("redis://" + server.Addr())
+			if err != nil {
+				t.Fatalf("NewRedis: %v", err)
+			}
+			return c
+		},
+		advance: server.FastForward,
+	})
+}
+
+// runSuite checks the behaviour both backends share
+func runSuite(t *testing.T, b backend) {
+	const ttl = 50 * time.Millisecond
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		run  func(t *testing.T, c cache.Cache)
+	}{
+		{"get and set expire after the ttl", func(t *testing.T, c cache.Cache) {
+			if _, ok, err := c.Get(ctx, "missing"); err != nil || ok {
+				t.Fatalf("Get(missing) = %v, %v, want a miss", ok, err)
+			}
+			if err := c.Set(ctx, "short", []byte("a"), ttl); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Set(ctx, "kept", []byte("b"), 0); err != nil {
+				t.Fatal(err)
+			}
+			if value, ok, err := c.Get(ctx, "short"); err != nil || !ok || string(value) != "a" {
+				t.Fatalf("Get(short) = %q, %v, %v, want a", value, ok, err)
+			}
+
+			b.advance(2 * ttl)
+			if _, ok, err := c.Get(ctx, "short"); err != nil || ok {
+				t.Fatalf("Get(short) after the ttl = %v, %v, want a miss", ok, err)
+			}
+			if value, ok, err := c.Get(ctx, "kept"); err != nil || !ok || string(value) != "b" {
+				t.Fatalf("Get(kept) = %q, %v, %v, want b", value, ok, err)
+			}
+
+			if err := c.Delete(ctx, "kept"); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok, err := c.Get(ctx, "kept"); err != nil || ok {
+				t.Fatalf("Get(kept) after Delete = %v, %v, want a miss", ok, err)
+			}
+		}},
+		{"set nx only sets missing keys", func(t *testing.T, c cache.Cache) {
+			if ok, err := c.SetNX(ctx, "key", []byte("first"), ttl); err != nil || !ok {
+				t.Fatalf("SetNX(key) = %v, %v, want true", ok, err)
+			}
+			if ok, err := c.SetNX(ctx, "key", []byte("second"), ttl); err != nil || ok {
+				t.Fatalf("SetNX(key) again = %v, %v, want false", ok, err)
+			}
+			if value, _, err := c.Get(ctx, "key"); err != nil || string(value) != "first" {
+				t.Fatalf("Get(key) = %q, %v, want first", value, err)
+			}
+
+			b.advance(2 * ttl)
+			if ok, err := c.SetNX(ctx, "key", []byte("third"), ttl); err != nil || !ok {
+				t.Fatalf("SetNX(key) after the ttl = %v, %v, want true", ok, err)
+			}
+		}},
+		{"incr and incr by count until the ttl of the first one", func(t *testing.T, c cache.Cache) {
+			for want := int64(1); want <= 3; want++ {
+				count, err := c.Incr(ctx, "counter", ttl)
+				if err != nil || count != want {
+					t.Fatalf("Incr(counter) = %d, %v, want %d", count, err, want)
+				}
+			}
+
+			b.advance(2 * ttl)
+			if count, err := c.Incr(ctx, "counter", ttl); err != nil || count != 1 {
+				t.Fatalf("Incr(counter) after the ttl = %d, %v, want 1", count, err)
+			}
+
+			if count, err := c.IncrBy(ctx, "bytes", 500, ttl); err != nil || count != 500 {
+				t.Fatalf("IncrBy(bytes, 500) = %d, %v, want 500", count, err)
+			}
+			if count, err := c.IncrBy(ctx, "bytes", -200, ttl); err != nil || count != 300 {
+				t.Fatalf("IncrBy(bytes, -200) = %d, %v, want 300", count, err)
+			}
+			b.advance(2 * ttl)
+			if count, err := c.IncrBy(ctx, "bytes", 7, ttl); err != nil || count != 7 {
+				t.Fatalf("IncrBy(bytes, 7) after the ttl = %d, %v, want 7", count, err)
+			}
+
+			if err := c.Set(ctx, "text", []byte("abc"), 0); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Incr(ctx, "text", ttl); err == nil {
+				t.Fatalf("Incr(text) succeeded, want an error")
+			}
+		}},
+		{"compare and delete only deletes the matching value", func(t *testing.T, c cache.Cache) {
+			if err := c.Set(ctx, "key", []byte("mine"), 0); err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := c.CompareAndDelete(ctx, "key", []byte("theirs")); err != nil || ok {
+				t.Fatalf("CompareAndDelete(theirs) = %v, %v, want false", ok, err)
+			}
+			if ok, err := c.CompareAndDelete(ctx, "key", []byte("mine")); err != nil || !ok {
+				t.Fatalf("CompareAndDelete(mine) = %v, %v, want true", ok, err)
+			}
+			if _, ok, err := c.Get(ctx, "key"); err != nil || ok {
+				t.Fatalf("Get(key) after CompareAndDelete = %v, %v, want a miss", ok, err)
+			}
+			if ok, err := c.CompareAndDelete(ctx, "key", []byte("mine")); err != nil || ok {
+				t.Fatalf("CompareAndDelete(missing) = %v, %v, want false", ok, err)
+			}
+		}},
+		{"try lock excludes other owners until unlocked or expired", func(t *testing.T, c cache.Cache) {
+			unlock, ok, err := cache.TryLock(ctx, c, "job", ttl)
+			if err != nil || !ok {
+				t.Fatalf("TryLock(job) = %v, %v, want the lock", ok, err)
+			}
+			if _, ok, err := cache.TryLock(ctx, c, "job", ttl); err != nil || ok {
+				t.Fatalf("TryLock(job) while held = %v, %v, want false", ok, err)
+			}
+			if err := unlock(); err != nil {
+				t.Fatal(err)
+			}
+
+			_, ok, err = cache.TryLock(ctx, c, "job", ttl)
+			if err != nil || !ok {
+				t.Fatalf("TryLock(job) after unlock = %v, %v, want the lock", ok, err)
+			}
+			b.advance(2 * ttl)
+
+			// The first owner's unlock mustn't release the new owner's lock
+			unlockNext, ok, err := cache.TryLock(ctx, c, "job", ttl)
+			if err != nil || !ok {
+				t.Fatalf("TryLock(job) after the ttl = %v, %v, want the lock", ok, err)
+			}
+			if err := unlock(); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok, err := cache.TryLock(ctx, c, "job", ttl); err != nil || ok {
+				t.Fatalf("TryLock(job) after a stale unlock = %v, %v, want false", ok, err)
+			}
+			if err := unlockNext(); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := b.newCache(t)
+			defer c.Close()
+			tc.run(t, c)
+		})
+	}
+}
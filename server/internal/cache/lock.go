@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// TryLock takes the lock name for ttl unless another owner holds it.
+// The ttl frees the lock of an owner that died, so it should outlast
+// the work it guards. unlock only releases the lock while this owner
+// still holds it
+func TryLock(ctx context.Context, c Cache, name string, ttl time.Duration) (unlock func() error, ok bool, err error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, false, err
+	}
+	value := []byte(hex.EncodeToString(token))
+	key := "lock:" + name
+
+	ok, err = c.SetNX(ctx, key, value, ttl)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	unlock = func() error {
+		_, err := c.CompareAndDelete(context.Background(), key, value)
+		return err
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+)
+
+// Memory is the cache of a single process, entries expire lazily and
+// are swept every second until Close
+type Memory struct {
+	entries    map[string]memoryEntry
+	maxEntries int
+	mutex      sync.Mutex
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+type memoryEntry struct {
+	value []byte
+	// expiresAt is zero for entries kept until deleted
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+func NewMemory() *Memory {
+	m := &Memory{
+		entries:    make(map[string]memoryEntry),
+		maxEntries: constants.CACHE_MEMORY_MAX_ENTRIES,
+		done:       make(chan struct{}),
+	}
+	go m.cleanupExpiredEntries()
+	return m
+}
+
+func (m *Memory) cleanupExpiredEntries() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case now := <-ticker.C:
+			m.mutex.Lock()
+			for key, entry := range m.entries {
+				if entry.expired(now) {
+					delete(m.entries, key)
+				}
+			}
+			m.mutex.Unlock()
+		}
+	}
+}
+
+// lookup returns the live entry at key, the caller holds the mutex
+func (m *Memory) lookup(key string) (memoryEntry, bool) {
+	entry, ok := m.entries[key]
+	if ok && entry.expired(time.Now()) {
+		delete(m.entries, key)
+		return entry, false
+	}
+	return entry, ok
+}
+
+// store writes the entry, the caller holds the mutex. A full cache
+// makes room by dropping the entry closest to expiring, entries
+// without a ttl go last
+func (m *Memory) store(key string, value []byte, ttl time.Duration) {
+	if _, exists := m.entries[key]; !exists && len(m.entries) >= m.maxEntries {
+		var evictKey string
+		var evict memoryEntry
+		for entryKey, entry := range m.entries {
+			if evictKey == "" || (!entry.expiresAt.IsZero() &&
+				(evict.expiresAt.IsZero() || entry.expiresAt.Before(evict.expiresAt))) {
+				evictKey, evict = entryKey, entry
+			}
+		}
+		delete(m.entries, evictKey)
+	}
+
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.store(key, value, ttl)
+	return nil
+}
+
+func (m *Memory) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.lookup(key); ok {
+		return false, nil
+	}
+	m.store(key, value, ttl)
+	return true, nil
+}
+
+func (m *Memory) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return m.IncrBy(ctx, key, 1, ttl)
+}
+
+func (m *Memory) IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok {
+		m.store(key, []byte(strconv.FormatInt(n, 10)), ttl)
+		return n, nil
+	}
+
+	count, err := strconv.ParseInt(string(entry.value), 10, 64)
+	if err != nil {
+		return 0, ErrNotInteger
+	}
+	count += n
+	entry.value = []byte(strconv.FormatInt(count, 10))
+	m.entries[key] = entry
+	return count, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *Memory) CompareAndDelete(ctx context.Context, key string, value []byte) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok || !bytes.Equal(entry.value, value) {
+		return false, nil
+	}
+	delete(m.entries, key)
+	return true, nil
+}
+
+func (m *Memory) Close() error {
+	m.closeOnce.Do(func() { close(m.done) })
+	return nil
+}
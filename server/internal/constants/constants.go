@@ -1,9 +1,62 @@
 package constants
 
+import "time"
+
 var QUERY_MINIMUM_LIMIT float64 = 5
 var QUERY_MAXIMUM_LIMIT float64 = 20
 
+var REMOTE_FETCH_MAX_BYTES int64 = 15 << 20 // 15 MiB
+var REMOTE_FETCH_MAX_REDIRECTS = 5
+
+var SEARCH_FUZZY_THRESHOLD = 0.3        // pg_trgm similarity, 0 to 1
+var SEARCH_DID_YOU_MEAN_THRESHOLD = 0.2 // pg_trgm word similarity, 0 to 1
+
+var SUGGEST_LIMIT = 10
+var SUGGEST_MAX_QUERY_LENGTH = 100
+
+var TIMESERIES_MAX_BUCKETS = 3660 // ten years of days
+var TIMESERIES_MAX_GROUPS = 10
+
+var INSIGHTS_MIN_GAP_DAYS = 7
+var INSIGHTS_TOP_AUTHORS = 3
+
+var FEED_ITEM_LIMIT = 50
+
+var EXPORT_BATCH_SIZE = 500
+
+var IMAGE_METADATA_BATCH_SIZE = 100 // articles per page of the image metadata backfill
+
+var CACHE_KEY_PREFIX = "hackernoon-articles:" // keys in a Redis shared with other apps
+var CACHE_MEMORY_MAX_ENTRIES = 10000
+
+var RATE_LIMIT_REQUESTS = 20 // per client ip and window
+var RATE_LIMIT_WINDOW = time.Minute
+
+var RESPONSE_CACHE_TTL = 5 * time.Minute
+
+// RESPONSE_CACHE_CONTROL lets browsers reuse a response for a minute
+// and a CDN for five, serving it stale while it revalidates
+var RESPONSE_CACHE_CONTROL = "public, max-age=60, s-maxage=300, stale-while-revalidate=60"
+
+var SNAPSHOT_BATCH_SIZE = 1000
+var SNAPSHOT_BLOB_MAX_BYTES int64 = 50 << 20 // 50 MiB
+
+var IMPORT_SOURCE_MAX_BYTES int64 = 200 << 20 // 200 MiB, ScrapedData fetched from a url
+
+var DAY_COUNT_MAX_ZONES = 50 // zones kept in the daily_article_counts rollup, added by rebuild-day-counts
+
 var NO_FILE_UPLOADED_ERROR = "there is no uploaded file associated with the given key"
-var RECORD_NOT_FOUND_ERROR = "record not found"
 
 var AnonymousTelNumber = 0000000000
+
+var REQUEST_MAX_BODY_SIZE = 4 << 20 // 4 MiB, fiber's default body limit
+
+var UPLOAD_MAX_FILE_SIZE int64 = 10 << 20 // 10 MiB
+var UPLOAD_MAX_FILES_PER_REQUEST = 10
+var UPLOAD_MAX_REQUEST_SIZE = 50 << 20         // 50 MiB
+var UPLOAD_DAILY_BYTES_QUOTA int64 = 500 << 20 // 500 MiB per API key
+var UPLOAD_DAILY_FILES_QUOTA = 200             // files per API key
+var UPLOAD_PRESIGN_EXPIRY = 15 * time.Minute
+var UPLOAD_PRESIGN_KEY_PREFIX = "uploads/"
+var UPLOAD_PENDING_EXPIRY = time.Hour // presigned uploads not completed by then are deleted
+var UPLOAD_PENDING_SWEEP_INTERVAL = 10 * time.Minute
@@ -4,12 +4,15 @@ import (
 	"log"
 
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/handlers/articles"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
 )
 
-func InitEventSubscribers() {
+func InitEventSubscribers(repos repository.Repositories) {
 	log.Println("Initiating global event subscribers...")
 
-	go articles.SaveScrapedArticles()
-	go articles.SaveScrapedArticlesV2()
+	articleHandler := articles.NewHandler(repos)
+
+	go articleHandler.SaveScrapedArticles()
+	go articleHandler.SaveScrapedArticlesV2()
 	// go articles.ScrapeSingleArticle()
 }
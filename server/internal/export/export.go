@@ -0,0 +1,225 @@
+// Package export writes articles with their author as CSV, NDJSON or
+// Parquet. Writers take one batch at a time so a whole table can be
+// streamed without holding it in memory
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+)
+
+var Formats = []string{FormatCSV, FormatNDJSON, FormatParquet}
+
+// ContentTypes of the formats, Parquet has no registered type
+var ContentTypes = map[string]string{
+	FormatCSV:     "text/csv; charset=utf-8",
+	FormatNDJSON:  "application/x-ndjson",
+	FormatParquet: "application/vnd.apache.parquet",
+}
+
+// Record is one exported article, flattened with its author
+type Record struct {
+	ID                  string    `json:"id" parquet:"id"`
+	TagIndex            string    `json:"tagIndex" parquet:"tagIndex"`
+	Title               string    `json:"title" parquet:"title"`
+	Href                string    `json:"href" parquet:"href"`
+	Tag                 string    `json:"tag" parquet:"tag"`
+	SourceTag           string    `json:"sourceTag" parquet:"sourceTag"`
+	PostedAt            time.Time `json:"postedAt" parquet:"postedAt,timestamp(millisecond)"`
+	ReadDuration        string    `json:"readDuration" parquet:"readDuration"`
+	ReadDurationMinutes int       `json:"readDurationMinutes" parquet:"readDurationMinutes"`
+	ImageUrl            string    `json:"imageUrl" parquet:"imageUrl"`
+	ImageWidth          int       `json:"imageWidth" parquet:"imageWidth"`
+	ImageHeight         int       `json:"imageHeight" parquet:"imageHeight"`
+	ClickCount          int       `json:"clickCount" parquet:"clickCount"`
+	AuthorID            string    `json:"authorID" parquet:"authorID"`
+	AuthorName          string    `json:"authorName" parquet:"authorName"`
+	AuthorPageUrl       string    `json:"authorPageUrl" parquet:"authorPageUrl"`
+	AuthorAvatarUrl     string    `json:"authorAvatarUrl" parquet:"authorAvatarUrl"`
+	CreatedAt           time.Time `json:"createdAt" parquet:"createdAt,timestamp(millisecond)"`
+	UpdatedAt           time.Time `json:"updatedAt" parquet:"updatedAt,timestamp(millisecond)"`
+}
+
+// csvHeader follows the order of csvRow
+var csvHeader = []string{
+	"id", "tagIndex", "title", "href", "tag", "sourceTag", "postedAt", "readDuration",
+	"readDurationMinutes", "imageUrl", "imageWidth", "imageHeight", "clickCount",
+	"authorID", "authorName", "authorPageUrl", "authorAvatarUrl", "createdAt", "updatedAt",
+}
+
+func (record Record) csvRow() []string {
+	return []string{
+		record.ID, record.TagIndex, record.Title, record.Href, record.Tag, record.SourceTag,
+		record.PostedAt.UTC().Format(time.RFC3339), record.ReadDuration,
+		strconv.Itoa(record.ReadDurationMinutes), record.ImageUrl,
+		strconv.Itoa(record.ImageWidth), strconv.Itoa(record.ImageHeight), strconv.Itoa(record.ClickCount),
+		record.AuthorID, record.AuthorName, record.AuthorPageUrl, record.AuthorAvatarUrl,
+		record.CreatedAt.UTC().Format(time.RFC3339), record.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func NewRecord(article models.Article) Record {
+	record := Record{
+		ID:                  article.ID,
+		TagIndex:            article.TagIndex,
+		Title:               article.Title,
+		Href:                article.Href,
+		Tag:                 article.Tag,
+		SourceTag:           article.SourceTag,
+		PostedAt:            article.PostedAt,
+		ReadDuration:        article.ReadDuration,
+		ReadDurationMinutes: article.ReadDurationMinutes,
+		ImageUrl:            article.ImageUrl,
+		ImageWidth:          article.ImageWidth,
+		ImageHeight:         article.ImageHeight,
+		ClickCount:          article.ClickCount,
+		AuthorID:            article.AuthorID,
+		CreatedAt:           article.CreatedAt,
+		UpdatedAt:           article.UpdatedAt,
+	}
+	if article.Author != nil {
+		record.AuthorName = article.Author.Name
+		record.AuthorPageUrl = article.Author.PageUrl
+		record.AuthorAvatarUrl = article.Author.AvatarUrl
+	}
+	return record
+}
+
+// Writer encodes batches of articles. Close flushes what is buffered
+// and ends the file, it doesn't close the underlying writer
+type Writer interface {
+	Write(articles []models.Article) error
+	Close() error
+}
+
+// NewWriter returns a writer of the format, gzip compressing the
+// output when asked to
+func NewWriter(output io.Writer, format string, compress bool) (Writer, error) {
+	var gzipWriter *gzip.Writer
+	if compress {
+		gzipWriter = gzip.NewWriter(output)
+		output = gzipWriter
+	}
+
+	var writer Writer
+	switch format {
+	case FormatCSV:
+		writer = newCSVWriter(output)
+	case FormatNDJSON:
+		writer = newNDJSONWriter(output)
+	case FormatParquet:
+		writer = newParquetWriter(output)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	if gzipWriter != nil {
+		return &gzipFormatWriter{Writer: writer, gzip: gzipWriter}, nil
+	}
+	return writer, nil
+}
+
+type gzipFormatWriter struct {
+	Writer
+	gzip *gzip.Writer
+}
+
+func (w *gzipFormatWriter) Close() error {
+	if err := w.Writer.Close(); err != nil {
+		return err
+	}
+	return w.gzip.Close()
+}
+
+type csvWriter struct {
+	csv         *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVWriter(output io.Writer) *csvWriter {
+	return &csvWriter{csv: csv.NewWriter(output)}
+}
+
+func (w *csvWriter) Write(articles []models.Article) error {
+	if !w.wroteHeader {
+		if err := w.csv.Write(csvHeader); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	for _, article := range articles {
+		if err := w.csv.Write(NewRecord(article).csvRow()); err != nil {
+			return err
+		}
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// Close writes the header of an empty export
+func (w *csvWriter) Close() error {
+	return w.Write(nil)
+}
+
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(output io.Writer) *ndjsonWriter {
+	buffer := bufio.NewWriter(output)
+	return &ndjsonWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+func (w *ndjsonWriter) Write(articles []models.Article) error {
+	for _, article := range articles {
+		if err := w.encoder.Encode(NewRecord(article)); err != nil {
+			return err
+		}
+	}
+	return w.buffer.Flush()
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.buffer.Flush()
+}
+
+// parquetWriter writes a row group per batch, the footer is only
+// written by Close
+type parquetWriter struct {
+	parquet *parquet.GenericWriter[Record]
+}
+
+func newParquetWriter(output io.Writer) *parquetWriter {
+	return &parquetWriter{parquet: parquet.NewGenericWriter[Record](output, parquet.Compression(&parquet.Snappy))}
+}
+
+func (w *parquetWriter) Write(articles []models.Article) error {
+	records := make([]Record, len(articles))
+	for i, article := range articles {
+		records[i] = NewRecord(article)
+	}
+	if _, err := w.parquet.Write(records); err != nil {
+		return err
+	}
+	return w.parquet.Flush()
+}
+
+func (w *parquetWriter) Close() error {
+	return w.parquet.Close()
+}
@@ -2,71 +2,46 @@ package articles
 
 import (
 	"log"
-	"strconv"
 	"time"
 
-	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
 	"github.com/gofiber/fiber/v2"
 )
 
-var GetAllArticles = func(c *fiber.Ctx) error {
-	articles := models.Article{}
-	limitParam := c.Query("limit")
-	articleIDCursorParam := c.Query("articleIDCursor")
+func (h *Handler) GetAllArticles(c *fiber.Ctx) error {
 	dateCursorParam := c.Query("dateCursor")
-	offsetParam := c.Query("offset")
 
-	limit, err := pkg.ValidateQueryLimit(limitParam)
+	sort, err := pkg.ParseArticleSort(c)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return err
 	}
-
-	if articleIDCursorParam == "" {
-		articleIDCursorParam = ""
+	page, err := pkg.ParsePageRequest(c, sort)
+	if err != nil {
+		return err
 	}
-
-	var parsedDateCursorParam time.Time
-	var offset int
-
-	log.Printf("dateCursorParam: %v\n", dateCursorParam)
-	if dateCursorParam != "" {
-		parsedDateCursorParam, err = time.Parse(time.RFC3339, dateCursorParam)
-		if err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "Invalid offset format! Must be an ISO 8601 string.")
-		}
-		log.Printf("parsedDateCursorParam: %v\n", parsedDateCursorParam)
+	filter, err := pkg.ParseArticleFilter(c)
+	if err != nil {
+		return err
 	}
 
-	if offsetParam != "" {
-		offset, err = strconv.Atoi(offsetParam)
+	// dateCursor starts the listing at a date, "time travel" in the client
+	log.Printf("dateCursorParam: %v\n", dateCursorParam)
+	if dateCursorParam != "" && filter.PostedTo.IsZero() {
+		filter.PostedTo, err = time.Parse(time.RFC3339, dateCursorParam)
 		if err != nil {
-			log.Println("Error converting offsetParam to an integer:", err)
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid dateCursor format! Must be an ISO 8601 string.")
 		}
-		log.Println(offset)
 	}
 
-	allArticles, count, err := articles.FindAllByPostedAt(int(limit), articleIDCursorParam, parsedDateCursorParam, offset)
+	articlePage, err := h.articles.FindPage(filter, sort, page)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
-	}
-
-	var prevCursor string
-	if len(allArticles) > 0 {
-		prevCursor = allArticles[len(allArticles)-1].ID
-	}
-
-	pagination := map[string]interface{}{
-		"limit":      limit,
-		"prevCursor": prevCursor,
-		"count":      count,
-		"offset":     offset,
+		return err
 	}
 
 	response := fiber.Map{
 		"status":     "success",
-		"data":       allArticles,
-		"pagination": pagination,
+		"data":       articlePage.Articles,
+		"pagination": pkg.CursorPagination(c, page, articlePage.PageInfo),
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
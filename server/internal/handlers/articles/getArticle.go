@@ -0,0 +1,65 @@
+package articles
+
+import (
+	"fmt"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// findArticle looks an article up by id or by tag index e.g a1234,
+// with its author loaded
+func (h *Handler) findArticle(idOrTagIndex string) (models.Article, error) {
+	if tagIndexPattern.MatchString(idOrTagIndex) {
+		return h.articles.FindByTagIndex(idOrTagIndex)
+	}
+
+	// Postgres rejects malformed uuids, they can't match an article
+	if _, err := uuid.Parse(idOrTagIndex); err != nil {
+		return models.Article{}, fmt.Errorf("article %w", models.ErrNotFound)
+	}
+
+	article, err := h.articles.FindOne(idOrTagIndex)
+	if err != nil {
+		return article, err
+	}
+
+	author, err := h.authors.FindOne(article.AuthorID)
+	if err != nil {
+		return article, err
+	}
+	article.Author = &author
+
+	return article, nil
+}
+
+// GetArticle returns the article with the id or tag index of the
+// route, its tags and the articles posted before and after it
+func (h *Handler) GetArticle(c *fiber.Ctx) error {
+	article, err := h.findArticle(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	neighbours, err := h.articles.FindNeighbours(article)
+	if err != nil {
+		return err
+	}
+	tags, err := h.tags.FindByArticle(article.ID)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"article":  article,
+			"tags":     tags,
+			"previous": neighbours.Previous,
+			"next":     neighbours.Next,
+		},
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
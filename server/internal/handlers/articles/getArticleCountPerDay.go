@@ -4,13 +4,11 @@ import (
 	"log"
 	"time"
 
-	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
 	"github.com/gofiber/fiber/v2"
 )
 
-var GetArticleCountPerDay = func(c *fiber.Ctx) error {
-	articles := models.Article{}
+func (h *Handler) GetArticleCountPerDay(c *fiber.Ctx) error {
 	limitParam := c.Query("limit")
 	dateCursorParam := c.Query("dateCursor")
 
@@ -19,6 +17,11 @@ var GetArticleCountPerDay = func(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
+	location, err := pkg.ParseTimezone(c)
+	if err != nil {
+		return err
+	}
+
 	var parsedDateCursorParam time.Time
 	log.Printf("dateCursorParam: %v\n", dateCursorParam)
 
@@ -29,13 +32,15 @@ var GetArticleCountPerDay = func(c *fiber.Ctx) error {
 			if err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, "Invalid date format! Must be YYYY-MM-DD or ISO 8601 string.")
 			}
+			// An instant falls on its day in the requested zone
+			parsedDateCursorParam = parsedDateCursorParam.In(location)
 		}
 		log.Printf("parsedDateCursorParam: %v\n", parsedDateCursorParam)
 	}
 
-	articleCountPerDay, err := articles.FindArticleCountPerDay(int(limit), parsedDateCursorParam)
+	articleCountPerDay, err := h.articles.FindArticleCountPerDay(int(limit), parsedDateCursorParam, location)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	var nextCursor string
@@ -47,7 +52,7 @@ var GetArticleCountPerDay = func(c *fiber.Ctx) error {
 	}
 
 	var totalDays int64
-	if err := articles.CountDistinctDays(&totalDays); err != nil {
+	if err := h.articles.CountDistinctDays(&totalDays, location); err != nil {
 		log.Printf("Error getting total days count: %v", err)
 		totalDays = 0
 	}
@@ -0,0 +1,56 @@
+package articles
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/insights"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetArticleInsights describes the publishing rhythm: streaks, the
+// busiest weekday and month, gaps longer than minGap days, the top
+// authors of every month or year and a year over year comparison
+func (h *Handler) GetArticleInsights(c *fiber.Ctx) error {
+	period := c.Query("period", repository.IntervalMonth)
+	if period != repository.IntervalMonth && period != repository.IntervalYear {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid period! Must be month or year.")
+	}
+
+	minGapDays := constants.INSIGHTS_MIN_GAP_DAYS
+	if minGapParam := c.Query("minGap"); minGapParam != "" {
+		var err error
+		minGapDays, err = strconv.Atoi(minGapParam)
+		if err != nil || minGapDays < 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid minGap! Must be a positive number of days.")
+		}
+	}
+
+	location, err := pkg.ParseTimezone(c)
+	if err != nil {
+		return err
+	}
+
+	dayCounts, err := h.articles.FindAuthorDayCounts(location)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(location)
+	articleInsights := insights.Compute(dayCounts, insights.Options{
+		Today:      time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		MinGapDays: minGapDays,
+		Period:     period,
+		TopAuthors: constants.INSIGHTS_TOP_AUTHORS,
+	})
+
+	response := fiber.Map{
+		"status": "success",
+		"data":   articleInsights,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,94 @@
+package articles
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetArticleTimeseries counts articles per day, week, month or year
+// between from and to, optionally one series per tag or author
+func (h *Handler) GetArticleTimeseries(c *fiber.Ctx) error {
+	interval := c.Query("interval", repository.IntervalDay)
+	if !slices.Contains(repository.Intervals, interval) {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid interval! Must be one of "+strings.Join(repository.Intervals, ", ")+".")
+	}
+
+	groupBy := c.Query("groupBy")
+	if groupBy != "" && groupBy != repository.GroupByTag && groupBy != repository.GroupByAuthor {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid groupBy! Must be tag or author.")
+	}
+
+	location, err := pkg.ParseTimezone(c)
+	if err != nil {
+		return err
+	}
+
+	// Dates are calendar days in the requested zone
+	now := time.Now().In(location)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if toParam := c.Query("to"); toParam != "" {
+		if to, err = time.Parse(time.DateOnly, toParam); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid to! Must be YYYY-MM-DD.")
+		}
+	}
+	from := defaultTimeseriesFrom(to, interval)
+	if fromParam := c.Query("from"); fromParam != "" {
+		if from, err = time.Parse(time.DateOnly, fromParam); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid from! Must be YYYY-MM-DD.")
+		}
+	}
+	if to.Before(from) {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid range! to must not be before from.")
+	}
+
+	bucketCount := 0
+	last := repository.TruncateDate(to, interval)
+	for bucket := repository.TruncateDate(from, interval); !bucket.After(last); bucket = repository.NextBucket(bucket, interval) {
+		bucketCount++
+		if bucketCount > constants.TIMESERIES_MAX_BUCKETS {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Range too large! At most %d %s buckets are allowed.", constants.TIMESERIES_MAX_BUCKETS, interval))
+		}
+	}
+
+	series, err := h.articles.FindTimeseries(repository.TimeseriesOptions{
+		Interval:   interval,
+		From:       from,
+		To:         to,
+		Location:   location,
+		GroupBy:    groupBy,
+		GroupLimit: constants.TIMESERIES_MAX_GROUPS,
+	})
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"status":   "success",
+		"data":     series,
+		"interval": interval,
+		"from":     from.Format(time.DateOnly),
+		"to":       to.Format(time.DateOnly),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// defaultTimeseriesFrom goes back far enough for a readable chart
+func defaultTimeseriesFrom(to time.Time, interval string) time.Time {
+	switch interval {
+	case repository.IntervalWeek:
+		return to.AddDate(0, 0, -7*11)
+	case repository.IntervalMonth:
+		return to.AddDate(0, -11, 0)
+	case repository.IntervalYear:
+		return to.AddDate(-4, 0, 0)
+	}
+	return to.AddDate(0, 0, -29)
+}
@@ -4,34 +4,43 @@ import (
 	"log"
 	"time"
 
-	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
 	"github.com/gofiber/fiber/v2"
 )
 
-var GetArticlesByDay = func(c *fiber.Ctx) error {
-	articles := models.Article{}
+// GetArticlesByDay returns the articles posted on the day of the
+// postedAt param, read as written, in the tz zone
+func (h *Handler) GetArticlesByDay(c *fiber.Ctx) error {
 	postedAtParam := c.Params("postedAt")
 	var parsedPostedAtParam time.Time
 	var err error
 
+	location, err := pkg.ParseTimezone(c)
+	if err != nil {
+		return err
+	}
+
 	log.Printf("postedAtParam: %v\n", postedAtParam)
 	if postedAtParam != "" {
-		parsedPostedAtParam, err = time.Parse(time.RFC3339, postedAtParam)
+		parsedPostedAtParam, err = time.Parse(time.DateOnly, postedAtParam)
 		if err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "Invalid offset format! Must be an ISO 8601 string.")
+			parsedPostedAtParam, err = time.Parse(time.RFC3339, postedAtParam)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid date format! Must be YYYY-MM-DD or ISO 8601 string.")
+			}
 		}
 		log.Printf("parsedDateCursorParam: %v\n", parsedPostedAtParam)
 	}
 
-	allArticles, err := articles.FindByPostedAt(parsedPostedAtParam)
+	allArticles, err := h.articles.FindByPostedAt(parsedPostedAtParam, location)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return err
 	}
 
 	response := fiber.Map{
-		"status":     "success",
-		"data":       allArticles,
+		"status": "success",
+		"data":   allArticles,
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
-}
\ No newline at end of file
+}
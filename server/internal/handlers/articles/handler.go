@@ -0,0 +1,21 @@
+package articles
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// Handler serves the article routes and runs the article jobs
+// against the injected repositories
+type Handler struct {
+	articles repository.ArticleRepository
+	authors  repository.AuthorRepository
+	tags     repository.TagRepository
+}
+
+func NewHandler(repos repository.Repositories) *Handler {
+	return &Handler{
+		articles: repos.Articles,
+		authors:  repos.Authors,
+		tags:     repos.Tags,
+	}
+}
@@ -0,0 +1,498 @@
+package articles
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/events"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+)
+
+type ScrapedData struct {
+	ScrapedAt     string           `json:"scraped_at"`
+	TotalArticles int              `json:"total_articles"`
+	Source        string           `json:"source"`
+	Category      string           `json:"category"`
+	Articles      []ScrapedArticle `json:"articles"`
+}
+
+const (
+	// ImportCreate saves the scraped articles that aren't saved yet,
+	// scraping the image of those scraped without one
+	ImportCreate = "create"
+	// ImportLinks sets the link of saved articles that have none
+	ImportLinks = "links"
+	// ImportImages replaces the image of saved articles whose image
+	// url still carries the query string of the scraped thumbnail
+	ImportImages = "images"
+	// ImportDiff only reports how the saved articles differ
+	ImportDiff = "diff"
+)
+
+var ImportModes = []string{ImportCreate, ImportLinks, ImportImages, ImportDiff}
+
+type ImportOptions struct {
+	// Source is the path or http(s) url of a ScrapedData file
+	Source string
+	Mode   string
+	// DryRun reports what would change without writing anything
+	DryRun bool
+	// ProgressFile records the title of every processed article, one
+	// per line. Failed articles aren't recorded so they are retried
+	ProgressFile string
+	// Resume skips the articles already in ProgressFile
+	Resume bool
+}
+
+// ImportDifference is a field of a saved article that differs from
+// the scraped one, Field is "article" for an article not saved yet
+type ImportDifference struct {
+	Title   string `json:"title"`
+	Field   string `json:"field"`
+	Saved   string `json:"saved"`
+	Scraped string `json:"scraped"`
+}
+
+type ImportFailure struct {
+	Title string `json:"title"`
+	Error string `json:"error"`
+}
+
+// ImportReport sums up an import. Created and Updated count what
+// would be written on a dry run, in diff mode they count the articles
+// that aren't saved and the saved ones that differ
+type ImportReport struct {
+	Source       string             `json:"source"`
+	Mode         string             `json:"mode"`
+	DryRun       bool               `json:"dryRun"`
+	ScrapedAt    string             `json:"scrapedAt"`
+	Total        int                `json:"total"`
+	WithoutImage int                `json:"withoutImage"` // scraped without an image url
+	Created      int                `json:"created"`
+	Updated      int                `json:"updated"`
+	Unchanged    int                `json:"unchanged"`
+	Skipped      int                `json:"skipped"`
+	Resumed      int                `json:"resumed"`
+	Failed       int                `json:"failed"`
+	Differences  []ImportDifference `json:"differences"`
+	Failures     []ImportFailure    `json:"failures"`
+	Duration     string             `json:"duration"`
+}
+
+// importOutcome is what happened to one scraped article
+type importOutcome int
+
+const (
+	importUnchanged importOutcome = iota
+	importCreated
+	importUpdated
+	importSkipped
+)
+
+// importer runs one import, the S3 client is only made once an
+// article has to be uploaded
+type importer struct {
+	handler  *Handler
+	options  ImportOptions
+	ctx      context.Context
+	s3Client *pkg.S3Client
+	report   *ImportReport
+}
+
+// Import applies a ScrapedData file to the saved articles according
+// to the mode. Articles are processed one at a time in file order and
+// an article that fails is reported without stopping the import
+func (h *Handler) Import(options ImportOptions) (ImportReport, error) {
+	start := time.Now()
+	report := ImportReport{
+		Source:      options.Source,
+		Mode:        options.Mode,
+		DryRun:      options.DryRun,
+		Differences: []ImportDifference{},
+		Failures:    []ImportFailure{},
+	}
+
+	switch options.Mode {
+	case ImportCreate, ImportLinks, ImportImages, ImportDiff:
+	default:
+		return report, fmt.Errorf("unsupported import mode %q", options.Mode)
+	}
+	if options.Resume && options.ProgressFile == "" {
+		return report, errors.New("resuming needs a progress file")
+	}
+
+	ctx := context.Background()
+
+	scrapedData, err := loadScrapedData(ctx, options.Source)
+	if err != nil {
+		return report, err
+	}
+	report.ScrapedAt = scrapedData.ScrapedAt
+	report.Total = len(scrapedData.Articles)
+	for _, scrapedArticle := range scrapedData.Articles {
+		if scrapedArticle.ImageUrl == "" {
+			report.WithoutImage++
+		}
+	}
+
+	processed := map[string]bool{}
+	if options.Resume {
+		processed, err = readImportProgress(options.ProgressFile)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	// A dry run must leave the progress file as it is so the real run
+	// can still resume from it
+	var progress *os.File
+	if options.ProgressFile != "" && !options.DryRun {
+		flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		if !options.Resume {
+			flags |= os.O_TRUNC
+		}
+		progress, err = os.OpenFile(options.ProgressFile, flags, 0o644)
+		if err != nil {
+			return report, fmt.Errorf("opening the progress file: %w", err)
+		}
+		defer progress.Close()
+	}
+
+	imp := &importer{handler: h, options: options, ctx: ctx, report: &report}
+
+	for _, scrapedArticle := range scrapedData.Articles {
+		if scrapedArticle.SourceTag == "" {
+			scrapedArticle.SourceTag = scrapedData.Category
+		}
+		if processed[scrapedArticle.Title] {
+			report.Resumed++
+			continue
+		}
+
+		outcome, err := imp.importArticle(scrapedArticle)
+		if err != nil {
+			log.Printf("Error importing article %s: %v", scrapedArticle.Title, err)
+			report.Failed++
+			report.Failures = append(report.Failures, ImportFailure{
+				Title: scrapedArticle.Title,
+				Error: err.Error(),
+			})
+			continue
+		}
+
+		switch outcome {
+		case importCreated:
+			report.Created++
+		case importUpdated:
+			report.Updated++
+		case importSkipped:
+			report.Skipped++
+		default:
+			report.Unchanged++
+		}
+
+		if progress != nil {
+			if _, err := fmt.Fprintln(progress, scrapedArticle.Title); err != nil {
+				return report, fmt.Errorf("recording progress: %w", err)
+			}
+		}
+	}
+
+	report.Duration = time.Since(start).String()
+	return report, nil
+}
+
+// loadScrapedData reads a ScrapedData file from a path or an http(s)
+// url
+func loadScrapedData(ctx context.Context, source string) (ScrapedData, error) {
+	var scrapedData ScrapedData
+	var data []byte
+	var err error
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		fetcher := pkg.NewRemoteFetcher()
+		fetcher.MaxBytes = constants.IMPORT_SOURCE_MAX_BYTES
+		remoteFile, fetchErr := fetcher.Fetch(ctx, source)
+		data, err = remoteFile.Data, fetchErr
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return scrapedData, fmt.Errorf("reading %s: %w", source, err)
+	}
+
+	if err := json.Unmarshal(data, &scrapedData); err != nil {
+		return scrapedData, fmt.Errorf("parsing %s: %w", source, err)
+	}
+	log.Printf("Successfully loaded %d articles from %s", len(scrapedData.Articles), source)
+	return scrapedData, nil
+}
+
+func readImportProgress(filename string) (map[string]bool, error) {
+	processed := map[string]bool{}
+
+	file, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return processed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening the progress file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		processed[scanner.Text()] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading the progress file: %w", err)
+	}
+	return processed, nil
+}
+
+func (imp *importer) importArticle(scrapedArticle ScrapedArticle) (importOutcome, error) {
+	if scrapedArticle.AuthorName == "" || scrapedArticle.Title == "" {
+		return importSkipped, nil
+	}
+
+	savedArticle, err := imp.handler.articles.FindByTitle(scrapedArticle.Title)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return importUnchanged, fmt.Errorf("finding the saved article: %w", err)
+	}
+	isSaved := savedArticle.ID != ""
+
+	switch imp.options.Mode {
+	case ImportCreate:
+		if isSaved {
+			return importUnchanged, nil
+		}
+		return imp.createArticle(scrapedArticle)
+	case ImportLinks:
+		if !isSaved {
+			return importSkipped, nil
+		}
+		return imp.updateLink(savedArticle, scrapedArticle)
+	case ImportImages:
+		if !isSaved {
+			return importSkipped, nil
+		}
+		return imp.updateImage(savedArticle, scrapedArticle)
+	default:
+		return imp.diffArticle(savedArticle, scrapedArticle)
+	}
+}
+
+func (imp *importer) createArticle(scrapedArticle ScrapedArticle) (importOutcome, error) {
+	if scrapedArticle.ImageUrl == "" && isBlackListed(scrapedArticle.Title) {
+		log.Printf("BlackListed: %s", scrapedArticle.Title)
+		return importSkipped, nil
+	}
+	if imp.options.DryRun {
+		return importCreated, nil
+	}
+
+	if scrapedArticle.ImageUrl == "" {
+		imageURL, err := ScrapeSingleArticleImage(scrapedArticle.URL)
+		if err != nil {
+			return importUnchanged, fmt.Errorf("scraping article image: %w", err)
+		}
+		scrapedArticle.ImageUrl = imageURL
+	}
+
+	s3Client, err := imp.s3()
+	if err != nil {
+		return importUnchanged, err
+	}
+	createdArticle, err := imp.handler.saveScrapedArticle(imp.ctx, s3Client, scrapedArticle)
+	if errors.Is(err, errArticleAlreadySaved) {
+		return importUnchanged, nil
+	}
+	if err != nil {
+		return importUnchanged, err
+	}
+	log.Println("Successfully created Article: ", createdArticle.Title)
+
+	events.EB.Publish("ARTICLE_SAVED", createdArticle)
+	return importCreated, nil
+}
+
+func (imp *importer) updateLink(savedArticle models.Article, scrapedArticle ScrapedArticle) (importOutcome, error) {
+	if savedArticle.Href != "" || scrapedArticle.URL == "" {
+		return importUnchanged, nil
+	}
+	if imp.options.DryRun {
+		return importUpdated, nil
+	}
+
+	savedArticle.Href = scrapedArticle.URL
+	if _, err := imp.handler.articles.Update(savedArticle); err != nil {
+		return importUnchanged, fmt.Errorf("updating article link: %w", err)
+	}
+	log.Println("Updated Article link successfully: ", savedArticle.Title)
+	return importUpdated, nil
+}
+
+func (imp *importer) updateImage(savedArticle models.Article, scrapedArticle ScrapedArticle) (importOutcome, error) {
+	if !strings.Contains(savedArticle.ImageUrl, "?") || scrapedArticle.ImageUrl == "" {
+		return importUnchanged, nil
+	}
+	if imp.options.DryRun {
+		return importUpdated, nil
+	}
+
+	s3Client, err := imp.s3()
+	if err != nil {
+		return importUnchanged, err
+	}
+
+	oldImageFilename := savedArticle.ImageFilename
+	if err := uploadArticleImage(imp.ctx, s3Client, &savedArticle, scrapedArticle.ImageUrl); err != nil {
+		return importUnchanged, err
+	}
+	if err := s3Client.DeleteFile(imp.ctx, oldImageFilename); err != nil {
+		log.Println("Error deleting old article image from s3 : ", err)
+	}
+
+	if _, err := imp.handler.articles.Update(savedArticle); err != nil {
+		return importUnchanged, fmt.Errorf("updating article image: %w", err)
+	}
+	log.Println("Updated Article image successfully: ", savedArticle.Title)
+	return importUpdated, nil
+}
+
+// diffArticle reports the scraped fields the saved article doesn't
+// match, the author is compared by name
+func (imp *importer) diffArticle(savedArticle models.Article, scrapedArticle ScrapedArticle) (importOutcome, error) {
+	if savedArticle.ID == "" {
+		imp.addDifference(scrapedArticle.Title, "article", "", scrapedArticle.URL)
+		return importCreated, nil
+	}
+
+	var authorName string
+	if savedArticle.AuthorID != "" {
+		author, err := imp.handler.authors.FindOne(savedArticle.AuthorID)
+		if err != nil && !errors.Is(err, models.ErrNotFound) {
+			return importUnchanged, fmt.Errorf("finding article's author: %w", err)
+		}
+		authorName = author.Name
+	}
+
+	fields := []struct{ name, saved, scraped string }{
+		{"href", savedArticle.Href, scrapedArticle.URL},
+		{"postedAt", formatImportTime(savedArticle.PostedAt), formatImportTime(scrapedArticle.PostedAt)},
+		{"author", authorName, scrapedArticle.AuthorName},
+		{"readDuration", savedArticle.ReadDuration, scrapedArticle.ReadDuration},
+		{"tag", savedArticle.Tag, scrapedArticle.Tag},
+	}
+
+	before := len(imp.report.Differences)
+	for _, field := range fields {
+		if field.saved != field.scraped {
+			imp.addDifference(scrapedArticle.Title, field.name, field.saved, field.scraped)
+		}
+	}
+	if len(imp.report.Differences) == before {
+		return importUnchanged, nil
+	}
+	return importUpdated, nil
+}
+
+func (imp *importer) addDifference(title, field, saved, scraped string) {
+	imp.report.Differences = append(imp.report.Differences, ImportDifference{
+		Title:   title,
+		Field:   field,
+		Saved:   saved,
+		Scraped: scraped,
+	})
+}
+
+func (imp *importer) s3() (*pkg.S3Client, error) {
+	if imp.s3Client != nil {
+		return imp.s3Client, nil
+	}
+	s3Client, err := (&pkg.S3Client{}).NewS3Client(imp.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating s3 client: %w", err)
+	}
+	imp.s3Client = s3Client
+	return s3Client, nil
+}
+
+func formatImportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func isBlackListed(title string) bool {
+	for _, blArticle := range blackListArticles {
+		if blArticle == title {
+			return true
+		}
+	}
+	return false
+}
+
+var blackListArticles = []string{
+	"Decentralized Applications Will Take Cryptocurrency to the Mainstream",
+	"What is The Bitcoin Halving and What Impact Will It Have on the Crypto Market?",
+	"A (Very) Basic Intro To Elliptic Curve Cryptography",
+	"How to generate a Bitcoin address — Technical address generation explanation",
+	"Top Crypto Exchange and Blockchain Companies to Watch for in Canada: 2020 Edition",
+	"Will Blockchain Produce a New Generation of Retail Algorithmic Traders?",
+	"Rising From the Ashes — A Tale of Bitcoin Crashes",
+	"Trace Mayer on Why You Must Own Your Bitcoin Private Keys",
+	"The Weaknesses of Blockchain and Decentralization",
+	"Is the Next Generation of Blockchain Technologies Already Upon Us?",
+	"An Overview of MakerDAO",
+	"The Universal Crypto Exchange APIs",
+	"Vijay Boyapati’s Bullish Case for Bitcoin",
+	"Coin Center’s Peter Van Valkenburg on Preserving the Freedom to Innovate with Public Blockchains",
+	"Jesse Powell is Building a Culture of Crypto Values at Kraken",
+	"Adam Back on a Decade of Bitcoin",
+	" Will Bitcoin enchain the world",
+	"Why are CBD & Kratom Vendors are Switching to Cryptocurrency?",
+	"Constructing Cryptocurrency Indices — Performance & Methodology",
+	"How to make money on arbitrage with cryptocurrencies",
+	"Questioning the Obsession with Blockchains and On-Chain Governance with Nic Carter",
+	"Francis Pouliot on the Network Effect of Money and Why Tokens Are Scams",
+	"Brave’s Brendan Eich on Fixing Online Advertising",
+	"Will Bitcoin enchain the world?",
+	"OTC crypto deals, part 2: Minimize your risks",
+	"What Bitcoin, Ethereum and other digital assets will become",
+	"Will We Ever Run Out of Bitcoin Wallets?",
+	"The Weaknesses of Blockchain and Decentralization.",
+	"A crypto-trader’s diary — week 13; TRON",
+	"The Verdict is In: I've Got a Vested Stake of a Hedgefund! Best News? You Can Be, Too!",
+	"MintMe's Attempting a Crypto Crowdfunding Method for Content Creators",
+	"A crypto-trader’s diary — week 12; Oyster coin",
+	"Does Your App Need Cryptocurrency Payments?",
+	"Shrimpy: A Week In Development [June 4]",
+	"Once bitcoin supply reaches it’s 21 million limit, how will btc price fluctuations change?",
+	"A crypto-trader’s diary — week 7; skin in the game",
+	"Why the specter of blockchain will be more important to humankind than AI",
+	"Don’t 100x at BitMEX: The Liquidation Price vs. Bankruptcy Price Gap Means you Lose",
+	"A crypto-trader’s diary — week 4",
+	"Bitcoin at 10",
+	"Crypto and gambling industries are more similar than it seems",
+	"A crypto-trader’s diary — week 1",
+	"Airdrops are screwing up the cryptocurrency market",
+	"5 Crypto Exchanges Reviewed (Plus a Winner!)",
+	"Securing Your Identity with Civic",
+	"145 Top Cryptocurrency Blogs and News Sites to Read Daily in 2020",
+	"7 Deadly ICO Sins That Will Scare Away Your Investors",
+	"The Case For Never-Ending Cryptocurrency Arbitrage Spreads",
+	"Resistance to Cryptocurrency as Explained by Behavioral Economics",
+	"Important Differences Between ICO Funding and Venture Capital Funding",
+	"7 reasons to HODL Bitcoin",
+}
@@ -0,0 +1,31 @@
+package articles
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RedirectShortLink sends /a/:tagIndex to the article on hackernoon
+// and counts the click
+func (h *Handler) RedirectShortLink(c *fiber.Ctx) error {
+	tagIndex := c.Params("tagIndex")
+	if !tagIndexPattern.MatchString(tagIndex) {
+		return fiber.NewError(fiber.StatusNotFound, "Invalid short link!")
+	}
+
+	article, err := h.articles.FindByTagIndex(tagIndex)
+	if err != nil {
+		return err
+	}
+	if article.Href == "" {
+		return fiber.NewError(fiber.StatusNotFound, "Article has no link yet!")
+	}
+
+	// A failed count must not cost the reader the redirect
+	if err := h.articles.IncrementClickCount(article.ID); err != nil {
+		log.Printf("Error counting click on %s: %v", tagIndex, err)
+	}
+
+	return c.Redirect(article.Href, fiber.StatusFound)
+}
@@ -0,0 +1,31 @@
+package articles
+
+import (
+	"log"
+	"slices"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+)
+
+// saveArticleTags links the article to the normalized tags of the
+// scraped tag strings, creating missing tags. Strings without a slug
+// e.g "#" are skipped
+func (h *Handler) saveArticleTags(article models.Article, tagNames []string) error {
+	var tagIDs []string
+	for _, tagName := range tagNames {
+		if models.TagSlug(tagName) == "" {
+			continue
+		}
+
+		tag, err := h.tags.FindOrCreate(tagName)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(tagIDs, tag.ID) {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+	}
+
+	log.Printf("Tagging %s with %d tags", article.Title, len(tagIDs))
+	return h.tags.SetArticleTags(article.ID, tagIDs)
+}
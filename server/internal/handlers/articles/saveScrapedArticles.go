@@ -2,22 +2,23 @@ package articles
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 
-	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/events"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
 )
 
-func SaveScrapedArticles() {
+// errArticleAlreadySaved is returned by saveScrapedArticle for a title
+// that is already saved
+var errArticleAlreadySaved = errors.New("article is already saved")
+
+func (h *Handler) SaveScrapedArticles() {
 	go func() {
 		scrapedArticleChan := make(chan events.DataEvent)
 		events.EB.Subscribe("SAVE_SCRAPED_ARTICLES", scrapedArticleChan)
-		article := models.Article{}
-		author := models.Author{}
-
-		imageProcessor := pkg.ImageProcessor{}
 
 		s3Client := pkg.S3Client{}
 
@@ -35,140 +36,181 @@ func SaveScrapedArticles() {
 				log.Printf("Invalid articleData type received: %T", scrapedArticle)
 				continue
 			}
-			if scrapedArticle.AuthorName == "" || scrapedArticle.Title == "" {
-				log.Printf("Article is has no title or author's name ")
-				continue
-			}
 			log.Printf("Saving article in progress %s:", scrapedArticle.Title)
 
-			savedArticle, err := article.FindByTitle(scrapedArticle.Title)
-			if err != nil && err.Error() != constants.RECORD_NOT_FOUND_ERROR {
-				log.Printf("Error finding the saved article: %v", err)
-				continue
-			}
-			if savedArticle.ID != "" {
+			createdArticle, err := h.saveScrapedArticle(ctx, newS3Client, scrapedArticle)
+			if errors.Is(err, errArticleAlreadySaved) {
 				log.Printf("Article is already saved: %s ", scrapedArticle.Title)
 				continue
 			}
-
-			articleAuthor, err := author.FindByName(scrapedArticle.AuthorName)
-			if err != nil && err.Error() != constants.RECORD_NOT_FOUND_ERROR {
-				log.Printf("Error finding article's author: %v", err)
-				continue
-			}
-
-			// Create author if doesn't exist
-			if articleAuthor.ID == "" {
-				avatarImgBuf, getImgErr := imageProcessor.GetImageFromURL(scrapedArticle.AuthorAvatarUrl)
-				if getImgErr != nil {
-					log.Println("Error getting author's avatar image from url : ", getImgErr)
-					continue
-				}
-
-				// Upload author's avatar Image
-				// if len(avatarImgBuf) > 0 && getImgErr == nil {
-				if len(avatarImgBuf) > 0 {
-					contentType, err := imageProcessor.GetContentTypeFromBinary(avatarImgBuf)
-					if err != nil {
-						log.Println("Error getting author avatar image content type : ", err)
-						continue
-					}
-					log.Println("Content type:", contentType)
-
-					imgFile := imageProcessor.BinaryToReader(avatarImgBuf)
-
-					uploadAvatarResp, err := newS3Client.UploadFile(
-						ctx,
-						imgFile,
-						scrapedArticle.AuthorAvatarUrl,
-						contentType,
-						0,
-					)
-					if err != nil {
-						log.Println("Error uploading file to s : ", err)
-						continue
-					}
-					articleAuthor, err = author.Create(models.Author{
-						Name:           scrapedArticle.AuthorName,
-						PageUrl:        scrapedArticle.AuthorPageURL,
-						AvatarUrl:      uploadAvatarResp.URL,
-						AvatarFilename: uploadAvatarResp.Filename,
-					})
-					if err != nil {
-						log.Println("Error creating author : ", err)
-						continue
-					}
-				}
-
-			}
-
-			article.AuthorID = articleAuthor.ID
-			article.Tag = scrapedArticle.Tag
-			article.Title = scrapedArticle.Title
-			article.Href = scrapedArticle.URL
-			article.PostedAt = scrapedArticle.PostedAt
-			article.ReadDuration = scrapedArticle.ReadDuration
-			article.ImageFilename = "ImageFilename.jpeg"
-			article.ImageUrl = scrapedArticle.ImageUrl
-
-			articleImgBuf, getImgErr := imageProcessor.GetImageFromURL(scrapedArticle.ImageUrl)
-			if getImgErr != nil {
-				log.Printf("Error getting articles's image from url : %v", err)
-				continue
-			}
-
-			// Upload article Image
-			// if len(articleImgBuf) > 0 && getImgErr == nil {
-			if len(articleImgBuf) > 0 {
-				contentType, err := imageProcessor.GetContentTypeFromBinary(articleImgBuf)
-				if err != nil {
-					log.Println("Error getting article image content type : ", err)
-					continue
-				}
-				log.Println("Content type:", contentType)
-
-				imgFile := imageProcessor.BinaryToReader(articleImgBuf)
-
-				uploadImageResp, err := newS3Client.UploadFile(
-					ctx,
-					imgFile,
-					scrapedArticle.ImageUrl,
-					contentType,
-					0,
-				)
-
-				if err != nil {
-					log.Println("Error uploading article image to s3", err)
-				}
-
-				article.ImageUrl = uploadImageResp.URL
-				article.ImageFilename = uploadImageResp.Filename
-			}
-
-			articleCount, err := article.FindCount()
-			if err != nil {
-				log.Println("Error finding article count : ", err)
-			}
-			if err == nil {
-				tagIndex := pkg.BuildTag(int(articleCount) + 1)
-				article.TagIndex = tagIndex
-			}
-
-			createdArticle, err := article.Create(article)
 			if err != nil {
-				log.Println("Error creating article : ", err)
+				log.Println("Error saving article : ", err)
 				continue
 			}
 			log.Println("Successfully created Article: ", createdArticle.Title)
+
+			events.EB.Publish("ARTICLE_SAVED", createdArticle)
 		}
 	}()
 }
 
-func SaveScrapedArticlesV2() {
+// saveScrapedArticle creates a scraped article with its author and
+// tags, uploading the author's avatar and the article's image to S3
+func (h *Handler) saveScrapedArticle(ctx context.Context, s3Client *pkg.S3Client,
+	scrapedArticle ScrapedArticle) (models.Article, error) {
+	if scrapedArticle.AuthorName == "" || scrapedArticle.Title == "" {
+		return models.Article{}, errors.New("article has no title or author's name")
+	}
+
+	savedArticle, err := h.articles.FindByTitle(scrapedArticle.Title)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return models.Article{}, fmt.Errorf("finding the saved article: %w", err)
+	}
+	if savedArticle.ID != "" {
+		return savedArticle, errArticleAlreadySaved
+	}
+
+	articleAuthor, err := h.findOrCreateScrapedAuthor(ctx, s3Client, scrapedArticle)
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	article := models.Article{}
+	article.AuthorID = articleAuthor.ID
+	article.Tag = scrapedArticle.Tag
+	article.Title = scrapedArticle.Title
+	article.Href = scrapedArticle.URL
+	article.PostedAt = scrapedArticle.PostedAt
+	article.ReadDuration = scrapedArticle.ReadDuration
+	article.SourceTag = scrapedArticle.SourceTag
+	article.ImageFilename = "ImageFilename.jpeg"
+	article.ImageUrl = scrapedArticle.ImageUrl
+
+	if err := uploadArticleImage(ctx, s3Client, &article, scrapedArticle.ImageUrl); err != nil {
+		return models.Article{}, err
+	}
+
+	articleCount, err := h.articles.FindCount()
+	if err != nil {
+		log.Println("Error finding article count : ", err)
+	}
+	if err == nil {
+		tagIndex := pkg.BuildTag(int(articleCount) + 1)
+		article.TagIndex = tagIndex
+	}
+
+	createdArticle, err := h.articles.Create(article)
+	if err != nil {
+		return models.Article{}, fmt.Errorf("creating article: %w", err)
+	}
+
+	// Tags holds every tag scraped for the article, Tag the one shown
+	tagNames := append([]string{scrapedArticle.Tag}, scrapedArticle.Tags...)
+	if err := h.saveArticleTags(createdArticle, tagNames); err != nil {
+		log.Println("Error saving article tags : ", err)
+	}
+
+	return createdArticle, nil
+}
+
+// findOrCreateScrapedAuthor finds the author of a scraped article by
+// name, creating it with its avatar uploaded when it doesn't exist
+func (h *Handler) findOrCreateScrapedAuthor(ctx context.Context, s3Client *pkg.S3Client,
+	scrapedArticle ScrapedArticle) (models.Author, error) {
+	imageProcessor := pkg.ImageProcessor{}
+
+	articleAuthor, err := h.authors.FindByName(scrapedArticle.AuthorName)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return articleAuthor, fmt.Errorf("finding article's author: %w", err)
+	}
+	if articleAuthor.ID != "" {
+		return articleAuthor, nil
+	}
+
+	avatarImgBuf, err := imageProcessor.GetImageFromURL(scrapedArticle.AuthorAvatarUrl)
+	if err != nil {
+		return articleAuthor, fmt.Errorf("getting author's avatar image from url: %w", err)
+	}
+	if len(avatarImgBuf) == 0 {
+		return articleAuthor, errors.New("author's avatar image is empty")
+	}
+
+	contentType, err := imageProcessor.GetContentTypeFromBinary(avatarImgBuf)
+	if err != nil {
+		return articleAuthor, fmt.Errorf("getting author avatar image content type: %w", err)
+	}
+	log.Println("Content type:", contentType)
+
+	uploadAvatarResp, err := s3Client.UploadFile(
+		ctx,
+		imageProcessor.BinaryToReader(avatarImgBuf),
+		scrapedArticle.AuthorAvatarUrl,
+		contentType,
+		0,
+	)
+	if err != nil {
+		return articleAuthor, fmt.Errorf("uploading author's avatar to s3: %w", err)
+	}
+
+	articleAuthor, err = h.authors.Create(models.Author{
+		Name:           scrapedArticle.AuthorName,
+		PageUrl:        scrapedArticle.AuthorPageURL,
+		AvatarUrl:      uploadAvatarResp.URL,
+		AvatarFilename: uploadAvatarResp.Filename,
+	})
+	if err != nil {
+		return articleAuthor, fmt.Errorf("creating author: %w", err)
+	}
+	return articleAuthor, nil
+}
+
+// uploadArticleImage downloads the image at imageURL, uploads it to
+// S3 and points the article at the upload with its metadata
+func uploadArticleImage(ctx context.Context, s3Client *pkg.S3Client, article *models.Article, imageURL string) error {
+	imageProcessor := pkg.ImageProcessor{}
+
+	articleImgBuf, err := imageProcessor.GetImageFromURL(imageURL)
+	if err != nil {
+		return fmt.Errorf("getting articles's image from url: %w", err)
+	}
+	if len(articleImgBuf) == 0 {
+		return nil
+	}
+
+	contentType, err := imageProcessor.GetContentTypeFromBinary(articleImgBuf)
+	if err != nil {
+		return fmt.Errorf("getting article image content type: %w", err)
+	}
+	log.Println("Content type:", contentType)
+
+	uploadImageResp, err := s3Client.UploadFile(
+		ctx,
+		imageProcessor.BinaryToReader(articleImgBuf),
+		imageURL,
+		contentType,
+		0,
+	)
+	if err != nil {
+		return fmt.Errorf("uploading article image to s3: %w", err)
+	}
+
+	article.ImageUrl = uploadImageResp.URL
+	article.ImageFilename = uploadImageResp.Filename
+
+	imageMetadata, err := imageProcessor.GetImageMetadata(articleImgBuf)
+	if err != nil {
+		log.Println("Error getting article image metadata : ", err)
+	}
+	if err == nil {
+		setArticleImageMetadata(article, imageMetadata)
+	}
+	return nil
+}
+
+func (h *Handler) SaveScrapedArticlesV2() {
 	go func() {
 		scrapedArticleChan := make(chan events.DataEvent)
 		events.EB.Subscribe("SAVE_SCRAPED_ARTICLES_V2", scrapedArticleChan)
-		article := models.Article{}
 
 		imageProcessor := pkg.ImageProcessor{}
 
@@ -194,8 +236,8 @@ func SaveScrapedArticlesV2() {
 			}
 			log.Printf("v2 Saving article in progress %s:", scrapedArticle.Title)
 
-			savedArticle, err := article.FindByTitle(scrapedArticle.Title)
-			if err != nil && err.Error() != constants.RECORD_NOT_FOUND_ERROR {
+			savedArticle, err := h.articles.FindByTitle(scrapedArticle.Title)
+			if err != nil && !errors.Is(err, models.ErrNotFound) {
 				log.Printf("Error finding the saved article: %v", err)
 				continue
 			}
@@ -231,14 +273,24 @@ func SaveScrapedArticlesV2() {
 
 				savedArticle.ImageUrl = uploadImageResp.URL
 				savedArticle.ImageFilename = uploadImageResp.Filename
+
+				imageMetadata, err := imageProcessor.GetImageMetadata(articleImgBuf)
+				if err != nil {
+					log.Println("Error getting article image metadata : ", err)
+				}
+				if err == nil {
+					setArticleImageMetadata(&savedArticle, imageMetadata)
+				}
 			}
 
-			updatedArticle, err := savedArticle.Update()
+			updatedArticle, err := h.articles.Update(savedArticle)
 			if err != nil {
 				log.Println("Error creating article : ", err)
 				continue
 			}
 			log.Println("Successfully updated Article: ", updatedArticle.Title)
+
+			events.EB.Publish("ARTICLE_SAVED", updatedArticle)
 		}
 	}()
 }
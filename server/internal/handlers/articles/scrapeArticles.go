@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/cache"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/events"
 	"github.com/chromedp/chromedp"
 )
@@ -27,6 +28,7 @@ type ScrapedArticle struct {
 	Tag             string   // Single tag from the tag div
 	Tags            []string // Keep for backward compatibility
 	ReadDuration    string   // Read duration like "4m", "2h", etc.
+	SourceTag       string   // Tag page the article was scraped from
 }
 
 type HackerNoonScraper struct {
@@ -638,6 +640,10 @@ func saveToJSON(articles []ScrapedArticle) error {
 	now := time.Now()
 	filename := fmt.Sprintf("%s-hackernoon-bitcoin-articles.json", now.Format("20060102-150405"))
 
+	for i := range articles {
+		articles[i].SourceTag = "bitcoin"
+	}
+
 	// Create JSON data
 	data := map[string]interface{}{
 		"scraped_at":     now.Format("2006-01-02T15:04:05Z"),
@@ -730,47 +736,47 @@ func ScrapeHackerNoonBitcoinArticlesOnly(maxArticles, scrolls int) ([]ScrapedArt
 // 	// }()
 // }
 
-// func init() {
-// 	fmt.Println("App initialized. Scheduling ScrapeHackerNoonBitcoinArticles() to run daily at 6pm EAT...")
-// 	go func() {
-// 		// Set timezone to East Africa Time (EAT - UTC+3)
-// 		eat, err := time.LoadLocation("Africa/Nairobi")
-// 		if err != nil {
-// 			log.Printf("Error loading EAT timezone: %v\n", err)
-// 			return
-// 		}
-
-// 		// Calculate initial delay to next 6pm EAT
-// 		now := time.Now().In(eat)
-// 		next6pm := time.Date(now.Year(), now.Month(), now.Day(), 18, 0, 0, 0, eat)
-
-// 		// If it's already past 6pm today, schedule for 6pm tomorrow
-// 		if now.After(next6pm) || now.Equal(next6pm) {
-// 			next6pm = next6pm.Add(24 * time.Hour)
-// 		}
-
-// 		initialDelay := next6pm.Sub(now)
-// 		log.Printf("Initial execution scheduled for: %v (in %v)\n", next6pm.Format("2006-01-02 15:04:05 MST"), initialDelay)
-
-// 		// Wait for initial 6pm
-// 		initialTimer := time.NewTimer(initialDelay)
-// 		<-initialTimer.C
-
-// 		// Execute first time
-// 		log.Printf("Executing ScrapeHackerNoonBitcoinArticles() at %v\n", time.Now().In(eat).Format("2006-01-02 15:04:05 MST"))
-// 		// ScrapeHackerNoonBitcoinArticles(30, 8)
-// 		ScrapeHackerNoonBitcoinArticles(200, 24)
-
-// 		ticker := time.NewTicker(24 * time.Hour)
-// 		defer ticker.Stop()
-
-// 		log.Println("Daily ticker started. Next executions every 24 hours at 6pm EAT")
-
-// 		// Execute daily at 6pm
-// 		for range ticker.C {
-// 			currentTime := time.Now().In(eat)
-// 			log.Printf("Executing ScrapeHackerNoonBitcoinArticles() at %v\n", currentTime.Format("2006-01-02 15:04:05 MST"))
-// 			ScrapeHackerNoonBitcoinArticles(200, 24)
-// 		}
-// 	}()
-// }
+// ScheduleDailyScrape runs ScrapeHackerNoonBitcoinArticles() daily at
+// 6pm EAT. Every replica schedules it but only the one taking the
+// day's lock scrapes, the lock is left to expire so replicas running
+// late don't scrape again
+func ScheduleDailyScrape(c cache.Cache) {
+	log.Println("Scheduling ScrapeHackerNoonBitcoinArticles() to run daily at 6pm EAT...")
+
+	// Set timezone to East Africa Time (EAT - UTC+3)
+	eat, err := time.LoadLocation("Africa/Nairobi")
+	if err != nil {
+		log.Printf("Error loading EAT timezone: %v\n", err)
+		return
+	}
+
+	for {
+		// Calculate delay to next 6pm EAT
+		now := time.Now().In(eat)
+		next6pm := time.Date(now.Year(), now.Month(), now.Day(), 18, 0, 0, 0, eat)
+
+		// If it's already past 6pm today, schedule for 6pm tomorrow
+		if !now.Before(next6pm) {
+			next6pm = next6pm.Add(24 * time.Hour)
+		}
+		log.Printf("Next execution scheduled for: %v (in %v)\n",
+			next6pm.Format("2006-01-02 15:04:05 MST"), next6pm.Sub(now))
+		time.Sleep(next6pm.Sub(now))
+
+		lockName := "daily-scrape:" + next6pm.Format("2006-01-02")
+		_, ok, err := cache.TryLock(context.Background(), c, lockName, 12*time.Hour)
+		if err != nil {
+			log.Printf("Error taking the %s lock: %v\n", lockName, err)
+			continue
+		}
+		if !ok {
+			log.Printf("Skipping ScrapeHackerNoonBitcoinArticles(), another replica holds %s\n", lockName)
+			continue
+		}
+
+		log.Printf("Executing ScrapeHackerNoonBitcoinArticles() at %v\n", time.Now().In(eat).Format("2006-01-02 15:04:05 MST"))
+		if err := ScrapeHackerNoonBitcoinArticles(200, 24); err != nil {
+			log.Printf("Error scraping articles: %v\n", err)
+		}
+	}
+}
@@ -2,19 +2,23 @@ package articles
 
 import (
 	"log"
+	"regexp"
+	"strconv"
 
-	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
 	"github.com/gofiber/fiber/v2"
 )
 
-var SearchArticles = func(c *fiber.Ctx) error {
-	articles := models.Article{}
+// tagIndexPattern matches the short article references built by
+// pkg.BuildTag e.g a1234
+var tagIndexPattern = regexp.MustCompile(`^a\d+$`)
+
+func (h *Handler) SearchArticles(c *fiber.Ctx) error {
 	searchQuery := c.Query("query")
-	limitParam := c.Query("limit")
-	// articleIDCursorParam := c.Query("articleIDCursor")
-	// dateCursorParam := c.Query("dateCursor")
-	// offsetParam := c.Query("offset")
+	fuzzyParam := c.Query("fuzzy")
+	thresholdParam := c.Query("threshold")
 
 	if searchQuery == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "Search query can't be empty!")
@@ -22,65 +26,68 @@ var SearchArticles = func(c *fiber.Ctx) error {
 
 	log.Println("searchQuery: ", searchQuery)
 
-	limit, err := pkg.ValidateQueryLimit(limitParam)
+	page, err := pkg.ParsePageRequest(c, repository.SortRelevance)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return err
 	}
 
-	// if articleIDCursorParam == "" {
-	// 	articleIDCursorParam = ""
-	// }
-
-	// var parsedDateCursorParam time.Time
-	var offset int
-
-	// if dateCursorParam != "" {
-	// 	parsedDateCursorParam, err := time.Parse(time.RFC3339, dateCursorParam)
-	// 	log.Println("dateCursorParam :", dateCursorParam)
-	// 	if err != nil {
-	// 		return fiber.NewError(fiber.StatusBadRequest, "Invalid offset format! Must be an ISO 8601 string.")
-	// 	}
-	// 	log.Printf("parsedDateCursorParam: %v\n", parsedDateCursorParam)
-
-	// }
-
-	// if offsetParam != "" {
-	// 	offset, err = strconv.Atoi(offsetParam)
-	// 	if err != nil {
-	// 		log.Println("Error converting offsetParam to an integer:", err)
-	// 	}
-	// 	log.Println(offset)
-	// }
-
-	// allArticles, count, err := articles.Search(searchQuery, articleIDCursorParam,
-	// 	parsedDateCursorParam, int(limit), offset)
-	// if err != nil {
-	// 	return fiber.NewError(fiber.StatusBadRequest, err.Error())
-	// }
-
-	allArticles, count, err := articles.SearchByTagIndex(searchQuery)
-	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	fuzzy := true
+	if fuzzyParam != "" {
+		fuzzy, err = strconv.ParseBool(fuzzyParam)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid fuzzy! Must be true or false.")
+		}
 	}
 
-	var prevCursor string
-	if len(allArticles) > 0 {
-		prevCursor = allArticles[len(allArticles)-1].ID
+	threshold := constants.SEARCH_FUZZY_THRESHOLD
+	if thresholdParam != "" {
+		threshold, err = strconv.ParseFloat(thresholdParam, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid threshold! Must be a number above 0 and at most 1.")
+		}
+	}
+
+	var articlePage repository.ArticlePage
+
+	// Exact article references skip the full-text search
+	if tagIndexPattern.MatchString(searchQuery) && page.Cursor == nil {
+		articlePage.Articles, articlePage.Total, err = h.articles.SearchByTagIndex(searchQuery)
+		if err != nil {
+			return err
+		}
 	}
 
-	pagination := map[string]interface{}{
-		"limit":      limit,
-		"prevCursor": prevCursor,
-		"count":      count,
-		"offset":     offset,
-		"query":      searchQuery,
+	if articlePage.Total == 0 {
+		articlePage, err = h.articles.Search(repository.SearchOptions{
+			Query:          searchQuery,
+			Fuzzy:          fuzzy,
+			FuzzyThreshold: threshold,
+			Page:           page,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
+	var didYouMean string
+	if articlePage.Total == 0 {
+		didYouMean, err = h.articles.DidYouMean(searchQuery, constants.SEARCH_DID_YOU_MEAN_THRESHOLD)
+		if err != nil {
+			log.Println("Error finding a did you mean suggestion:", err)
+		}
+	}
+
+	pagination := pkg.CursorPagination(c, page, articlePage.PageInfo)
+	pagination["query"] = searchQuery
+
 	response := fiber.Map{
 		"status":     "success",
-		"data":       allArticles,
+		"data":       articlePage.Articles,
 		"pagination": pagination,
 	}
+	if didYouMean != "" {
+		response["didYouMean"] = didYouMean
+	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
@@ -0,0 +1,76 @@
+package articles
+
+import (
+	"log"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+)
+
+func setArticleImageMetadata(article *models.Article, metadata pkg.ImageMetadata) {
+	article.ImageWidth = metadata.Width
+	article.ImageHeight = metadata.Height
+	article.ImageAspectRatio = metadata.AspectRatio
+	article.ImageDominantColor = metadata.DominantColor
+	article.ImageBlurHash = metadata.BlurHash
+}
+
+// UpdateArticleImageMetadata backfills dimensions, dominant colour
+// and blurhash for articles saved before image metadata existed. It
+// pages through the articles by id, and articles whose image can't be
+// fetched, decoded or saved are marked so later runs skip them. Run
+// it with the backfill-image-metadata command
+func (h *Handler) UpdateArticleImageMetadata() {
+	imageProcessor := pkg.ImageProcessor{}
+
+	var afterID string
+	var updated, failed int
+	for {
+		articles, err := h.articles.FindAllWithoutImageMetadata(afterID, constants.IMAGE_METADATA_BATCH_SIZE)
+		if err != nil {
+			log.Printf("Error finding articles: %v", err)
+			break
+		}
+		if len(articles) == 0 {
+			break
+		}
+		afterID = articles[len(articles)-1].ID
+
+		for _, currArticle := range articles {
+			articleImgBuf, err := imageProcessor.GetImageFromURL(currArticle.ImageUrl)
+			if err != nil {
+				log.Println("Error getting article's image from url : ", err)
+				failed++
+				h.markImageMetadataFailed(currArticle)
+				continue
+			}
+
+			imageMetadata, err := imageProcessor.GetImageMetadata(articleImgBuf)
+			if err != nil {
+				log.Printf("Error getting image metadata for article %s: %v", currArticle.Title, err)
+				failed++
+				h.markImageMetadataFailed(currArticle)
+				continue
+			}
+			setArticleImageMetadata(&currArticle, imageMetadata)
+
+			updatedArticle, err := h.articles.Update(currArticle)
+			if err != nil {
+				log.Println("Error updating article image metadata: ", err)
+				failed++
+				h.markImageMetadataFailed(currArticle)
+				continue
+			}
+			updated++
+			log.Println("Updated Article Image Metadata successfully: ", updatedArticle.Title)
+		}
+	}
+	log.Printf("Image metadata updated for %d articles, %d images failed", updated, failed)
+}
+
+func (h *Handler) markImageMetadataFailed(article models.Article) {
+	if err := h.articles.MarkImageMetadataFailed(article.ID); err != nil {
+		log.Printf("Error marking image metadata of article %s as failed: %v", article.Title, err)
+	}
+}
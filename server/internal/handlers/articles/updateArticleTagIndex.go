@@ -3,14 +3,12 @@ package articles
 import (
 	"log"
 
-	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
 )
 
-func UpdateArticleTagIndex() {
-	article := models.Article{}
+func (h *Handler) UpdateArticleTagIndex() {
 
-	articles, _, err := article.FindAllByPostedAtInAsc(8000)
+	articles, _, err := h.articles.FindAllByPostedAtInAsc(8000)
 	if err != nil {
 		log.Printf("Error finding articles: %v", err)
 	}
@@ -26,7 +24,7 @@ func UpdateArticleTagIndex() {
 		// currArticle.TagIndex = pkg.BuildTag(index + 1)
 		log.Println("tagIndex:", tagIndex)
 
-		updatedArticle, err := currArticle.Update()
+		updatedArticle, err := h.articles.Update(currArticle)
 		if err != nil {
 			log.Println("Error updating article Tag Index: ", err)
 			continue
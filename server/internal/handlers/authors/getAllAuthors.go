@@ -0,0 +1,41 @@
+package authors
+
+import (
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *Handler) GetAllAuthors(c *fiber.Ctx) error {
+	searchQuery := strings.TrimSpace(c.Query("q"))
+	sortParam := c.Query("sort")
+
+	sort := repository.SortMostArticles
+	if sortParam != "" {
+		var ok bool
+		sort, ok = repository.AuthorSorts[sortParam]
+		if !ok {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid sort! Must be one of articles, latest or name.")
+		}
+	}
+
+	page, err := pkg.ParsePageRequest(c, sort)
+	if err != nil {
+		return err
+	}
+
+	authorPage, err := h.authors.FindPage(repository.AuthorFilter{Search: searchQuery}, sort, page)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"status":     "success",
+		"data":       authorPage.Authors,
+		"pagination": pkg.CursorPagination(c, page, authorPage.PageInfo),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,43 @@
+package authors
+
+import (
+	"fmt"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// findAuthor loads the author of the route's id. Postgres rejects
+// malformed uuids, they can't match an author
+func (h *Handler) findAuthor(c *fiber.Ctx) (models.Author, error) {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return models.Author{}, fmt.Errorf("author %w", models.ErrNotFound)
+	}
+
+	return h.authors.FindOne(id)
+}
+
+// GetAuthor returns the author's profile with stats on their articles
+func (h *Handler) GetAuthor(c *fiber.Ctx) error {
+	author, err := h.findAuthor(c)
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.authors.FindStats(author.ID)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"author": author,
+			"stats":  stats,
+		},
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
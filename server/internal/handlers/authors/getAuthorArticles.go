@@ -0,0 +1,43 @@
+package authors
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAuthorArticles lists the author's articles, accepting the same
+// filters and sorts as the article listing
+func (h *Handler) GetAuthorArticles(c *fiber.Ctx) error {
+	author, err := h.findAuthor(c)
+	if err != nil {
+		return err
+	}
+
+	sort, err := pkg.ParseArticleSort(c)
+	if err != nil {
+		return err
+	}
+	page, err := pkg.ParsePageRequest(c, sort)
+	if err != nil {
+		return err
+	}
+	filter, err := pkg.ParseArticleFilter(c)
+	if err != nil {
+		return err
+	}
+	filter.AuthorID = author.ID
+	filter.AuthorName = ""
+
+	articlePage, err := h.articles.FindPage(filter, sort, page)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"status":     "success",
+		"data":       articlePage.Articles,
+		"pagination": pkg.CursorPagination(c, page, articlePage.PageInfo),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,18 @@
+package authors
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// Handler serves the author directory routes
+type Handler struct {
+	articles repository.ArticleRepository
+	authors  repository.AuthorRepository
+}
+
+func NewHandler(repos repository.Repositories) *Handler {
+	return &Handler{
+		articles: repos.Articles,
+		authors:  repos.Authors,
+	}
+}
@@ -0,0 +1,70 @@
+package exports
+
+import (
+	"bufio"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/export"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportArticles streams every article matching the article listing
+// filters as csv, ndjson or parquet. The body is gzip encoded when
+// the client accepts it, gzip=true downloads a .gz file instead
+func (h *Handler) ExportArticles(c *fiber.Ctx) error {
+	format := c.Query("format", export.FormatCSV)
+	if !slices.Contains(export.Formats, format) {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid format! Must be one of "+strings.Join(export.Formats, ", ")+".")
+	}
+
+	filter, err := pkg.ParseArticleFilter(c)
+	if err != nil {
+		return err
+	}
+
+	filename := "articles." + format
+	compress := c.QueryBool("gzip")
+	if compress {
+		filename += ".gz"
+		c.Set(fiber.HeaderContentType, "application/gzip")
+	} else {
+		c.Set(fiber.HeaderContentType, export.ContentTypes[format])
+		c.Vary(fiber.HeaderAcceptEncoding)
+		if strings.Contains(c.Get(fiber.HeaderAcceptEncoding), "gzip") {
+			c.Set(fiber.HeaderContentEncoding, "gzip")
+			compress = true
+		}
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	// The status and headers are sent before the first row is read, an
+	// error midway can only cut the body short
+	c.Context().SetBodyStreamWriter(func(output *bufio.Writer) {
+		writer, err := export.NewWriter(output, format, compress)
+		if err != nil {
+			log.Println("Error creating export writer : ", err)
+			return
+		}
+
+		err = h.articles.Stream(filter, constants.EXPORT_BATCH_SIZE, func(articles []models.Article) error {
+			if err := writer.Write(articles); err != nil {
+				return err
+			}
+			return output.Flush()
+		})
+		if err != nil {
+			log.Println("Error exporting articles : ", err)
+			return
+		}
+		if err := writer.Close(); err != nil {
+			log.Println("Error closing export : ", err)
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package exports
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// Handler serves the bulk export of articles
+type Handler struct {
+	articles repository.ArticleRepository
+}
+
+func NewHandler(repos repository.Repositories) *Handler {
+	return &Handler{
+		articles: repos.Articles,
+	}
+}
@@ -0,0 +1,89 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string        `xml:"id"`
+	Title     string        `xml:"title"`
+	Updated   string        `xml:"updated"`
+	Published string        `xml:"published"`
+	Author    *atomAuthor   `xml:"author"`
+	Links     []atomLink    `xml:"link"`
+	Category  *atomCategory `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// GetAtomFeed serves the newest articles as Atom 1.0
+func (h *Handler) GetAtomFeed(c *fiber.Ctx) error {
+	f, err := h.loadFeed(c, "atom")
+	if f == nil {
+		return err
+	}
+
+	document := atomFeed{
+		ID:       f.selfURL,
+		Title:    f.title,
+		Subtitle: f.description,
+		Updated:  f.buildDate().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.homeURL, Rel: "alternate"},
+		},
+		Entries: make([]atomEntry, 0, len(f.articles)),
+	}
+	for _, article := range f.articles {
+		entry := atomEntry{
+			ID:        "urn:uuid:" + article.ID,
+			Title:     article.Title,
+			Updated:   article.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: article.PostedAt.UTC().Format(time.RFC3339),
+			Links:     []atomLink{{Href: f.articleURL(article), Rel: "alternate"}},
+		}
+		if article.Author != nil {
+			entry.Author = &atomAuthor{Name: article.Author.Name, URI: article.Author.PageUrl}
+		}
+		if article.Tag != "" {
+			entry.Category = &atomCategory{Term: article.Tag}
+		}
+		if article.ImageUrl != "" {
+			entry.Links = append(entry.Links, atomLink{
+				Href: article.ImageUrl,
+				Rel:  "enclosure",
+				Type: imageType(article.ImageUrl),
+			})
+		}
+		document.Entries = append(document.Entries, entry)
+	}
+
+	return writeXML(c, "application/atom+xml; charset=utf-8", document)
+}
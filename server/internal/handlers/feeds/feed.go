@@ -0,0 +1,123 @@
+package feeds
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// feed is what the RSS, Atom and JSON renderings share
+type feed struct {
+	title       string
+	description string
+	homeURL     string
+	selfURL     string
+	updated     time.Time
+	articles    []models.Article
+}
+
+// loadFeed reads the newest articles matching the article listing
+// filters and answers conditional requests. It returns nil when a
+// 304 was sent
+func (h *Handler) loadFeed(c *fiber.Ctx, format string) (*feed, error) {
+	filter, err := pkg.ParseArticleFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	articlePage, err := h.articles.FindPage(filter, repository.SortNewest,
+		repository.PageRequest{Limit: constants.FEED_ITEM_LIMIT})
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := pkg.PublicBaseURL(c)
+	f := &feed{
+		title:       feedTitle(filter),
+		description: "The latest articles indexed from HackerNoon",
+		homeURL:     baseURL,
+		selfURL:     baseURL + c.OriginalURL(),
+		articles:    articlePage.Articles,
+	}
+
+	// The validators change whenever an article of the feed is added,
+	// removed or updated
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n", format, c.Context().QueryArgs().String())
+	for _, article := range f.articles {
+		fmt.Fprintf(hash, "%s %d\n", article.ID, article.UpdatedAt.UnixNano())
+		if article.UpdatedAt.After(f.updated) {
+			f.updated = article.UpdatedAt
+		}
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	etag := `W/"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+	if pkg.NotModified(c, etag, f.updated) {
+		return nil, c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return f, nil
+}
+
+// buildDate is when the feed last changed, now for an empty feed
+func (f *feed) buildDate() time.Time {
+	if f.updated.IsZero() {
+		return time.Now().UTC()
+	}
+	return f.updated.UTC()
+}
+
+func feedTitle(filter repository.ArticleFilter) string {
+	var parts []string
+	if filter.Tag != "" {
+		parts = append(parts, strings.TrimPrefix(filter.Tag, "#"))
+	}
+	if filter.SourceTag != "" {
+		parts = append(parts, filter.SourceTag)
+	}
+	title := "HackerNoon articles"
+	if len(parts) > 0 {
+		title = "HackerNoon " + strings.Join(parts, ", ") + " articles"
+	}
+	if filter.AuthorName != "" {
+		title += " by " + filter.AuthorName
+	}
+	return title
+}
+
+// articleURL links to the article on HackerNoon, or to its short link
+// while the link is missing
+func (f *feed) articleURL(article models.Article) string {
+	if article.Href != "" {
+		return article.Href
+	}
+	return f.homeURL + "/a/" + article.TagIndex
+}
+
+func authorName(article models.Article) string {
+	if article.Author == nil {
+		return ""
+	}
+	return article.Author.Name
+}
+
+// imageType guesses the content type of an article image from its
+// extension, scraped images are JPEG unless told otherwise
+func imageType(imageURL string) string {
+	extension := path.Ext(strings.SplitN(imageURL, "?", 2)[0])
+	if contentType := mime.TypeByExtension(extension); strings.HasPrefix(contentType, "image/") {
+		return contentType
+	}
+	return "image/jpeg"
+}
@@ -0,0 +1,16 @@
+package feeds
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// Handler serves the article feeds in RSS, Atom and JSON Feed
+type Handler struct {
+	articles repository.ArticleRepository
+}
+
+func NewHandler(repos repository.Repositories) *Handler {
+	return &Handler{
+		articles: repos.Articles,
+	}
+}
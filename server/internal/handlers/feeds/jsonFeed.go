@@ -0,0 +1,89 @@
+package feeds
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JSON Feed 1.1, https://www.jsonfeed.org/version/1.1/
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Language    string         `json:"language"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	Title         string               `json:"title"`
+	ContentText   string               `json:"content_text"`
+	Image         string               `json:"image,omitempty"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name   string `json:"name"`
+	URL    string `json:"url,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+type jsonFeedAttachment struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+}
+
+// GetJSONFeed serves the newest articles as JSON Feed 1.1
+func (h *Handler) GetJSONFeed(c *fiber.Ctx) error {
+	f, err := h.loadFeed(c, "json")
+	if f == nil {
+		return err
+	}
+
+	document := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.title,
+		HomePageURL: f.homeURL,
+		FeedURL:     f.selfURL,
+		Description: f.description,
+		Language:    "en",
+		Items:       make([]jsonFeedItem, 0, len(f.articles)),
+	}
+	for _, article := range f.articles {
+		// Only the title and metadata are scraped, content_text is
+		// required so it carries the read duration
+		item := jsonFeedItem{
+			ID:            article.ID,
+			URL:           f.articleURL(article),
+			Title:         article.Title,
+			ContentText:   article.Title + " (" + article.ReadDuration + " read)",
+			Image:         article.ImageUrl,
+			DatePublished: article.PostedAt.UTC().Format(time.RFC3339),
+			DateModified:  article.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if article.Author != nil {
+			item.Authors = []jsonFeedAuthor{{
+				Name:   article.Author.Name,
+				URL:    article.Author.PageUrl,
+				Avatar: article.Author.AvatarUrl,
+			}}
+		}
+		if article.Tag != "" {
+			item.Tags = []string{article.Tag}
+		}
+		if article.ImageUrl != "" {
+			item.Attachments = []jsonFeedAttachment{{URL: article.ImageUrl, MimeType: imageType(article.ImageUrl)}}
+		}
+		document.Items = append(document.Items, item)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(document, "application/feed+json; charset=utf-8")
+}
@@ -0,0 +1,97 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title     string        `xml:"title"`
+	Link      string        `xml:"link"`
+	GUID      rssGUID       `xml:"guid"`
+	PubDate   string        `xml:"pubDate"`
+	Creator   string        `xml:"dc:creator,omitempty"`
+	Category  string        `xml:"category,omitempty"`
+	Enclosure *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// GetRSSFeed serves the newest articles as RSS 2.0
+func (h *Handler) GetRSSFeed(c *fiber.Ctx) error {
+	f, err := h.loadFeed(c, "rss")
+	if f == nil {
+		return err
+	}
+
+	channel := rssChannel{
+		Title:         f.title,
+		Link:          f.homeURL,
+		Description:   f.description,
+		Language:      "en",
+		LastBuildDate: f.buildDate().Format(time.RFC1123Z),
+		Self:          atomLink{Href: f.selfURL, Rel: "self", Type: "application/rss+xml"},
+		Items:         make([]rssItem, 0, len(f.articles)),
+	}
+	for _, article := range f.articles {
+		item := rssItem{
+			Title:    article.Title,
+			Link:     f.articleURL(article),
+			GUID:     rssGUID{IsPermaLink: "false", Value: "urn:uuid:" + article.ID},
+			PubDate:  article.PostedAt.UTC().Format(time.RFC1123Z),
+			Creator:  authorName(article),
+			Category: article.Tag,
+		}
+		// The size of remote images is unknown, 0 is the usual stand in
+		if article.ImageUrl != "" {
+			item.Enclosure = &rssEnclosure{URL: article.ImageUrl, Length: 0, Type: imageType(article.ImageUrl)}
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	return writeXML(c, "application/rss+xml; charset=utf-8", rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: channel,
+	})
+}
+
+func writeXML(c *fiber.Ctx, contentType string, document interface{}) error {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Status(fiber.StatusOK).Send(append([]byte(xml.Header), body...))
+}
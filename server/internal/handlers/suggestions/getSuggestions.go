@@ -0,0 +1,29 @@
+package suggestions
+
+import (
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *Handler) GetSuggestions(c *fiber.Ctx) error {
+	query := strings.TrimSpace(c.Query("q"))
+
+	if query == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Suggestion query can't be empty!")
+	}
+	if len(query) > constants.SUGGEST_MAX_QUERY_LENGTH {
+		return fiber.NewError(fiber.StatusBadRequest, "Suggestion query is too long!")
+	}
+
+	suggestions := h.index.Suggest(query, constants.SUGGEST_LIMIT)
+
+	response := fiber.Map{
+		"status": "success",
+		"data":   suggestions,
+		"query":  query,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
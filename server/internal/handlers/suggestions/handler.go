@@ -0,0 +1,16 @@
+package suggestions
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/autocomplete"
+)
+
+// Handler serves autocomplete suggestions from the shared index
+type Handler struct {
+	index *autocomplete.Index
+}
+
+func NewHandler(index *autocomplete.Index) *Handler {
+	return &Handler{
+		index: index,
+	}
+}
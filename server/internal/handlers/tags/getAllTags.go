@@ -0,0 +1,41 @@
+package tags
+
+import (
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *Handler) GetAllTags(c *fiber.Ctx) error {
+	searchQuery := strings.TrimSpace(c.Query("q"))
+	sortParam := c.Query("sort")
+
+	sort := repository.SortMostTagged
+	if sortParam != "" {
+		var ok bool
+		sort, ok = repository.TagSorts[sortParam]
+		if !ok {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid sort! Must be one of articles or slug.")
+		}
+	}
+
+	page, err := pkg.ParsePageRequest(c, sort)
+	if err != nil {
+		return err
+	}
+
+	tagPage, err := h.tags.FindPage(repository.TagFilter{Search: searchQuery}, sort, page)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"status":     "success",
+		"data":       tagPage.Tags,
+		"pagination": pkg.CursorPagination(c, page, tagPage.PageInfo),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,44 @@
+package tags
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTagArticles lists the articles of the tag with the slug or alias
+// of the route, accepting the same filters and sorts as the article
+// listing
+func (h *Handler) GetTagArticles(c *fiber.Ctx) error {
+	tag, err := h.tags.FindBySlug(c.Params("slug"))
+	if err != nil {
+		return err
+	}
+
+	sort, err := pkg.ParseArticleSort(c)
+	if err != nil {
+		return err
+	}
+	page, err := pkg.ParsePageRequest(c, sort)
+	if err != nil {
+		return err
+	}
+	filter, err := pkg.ParseArticleFilter(c)
+	if err != nil {
+		return err
+	}
+	filter.TagSlug = tag.Slug
+
+	articlePage, err := h.articles.FindPage(filter, sort, page)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"status":     "success",
+		"data":       articlePage.Articles,
+		"tag":        tag,
+		"pagination": pkg.CursorPagination(c, page, articlePage.PageInfo),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,18 @@
+package tags
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// Handler serves the tag routes
+type Handler struct {
+	articles repository.ArticleRepository
+	tags     repository.TagRepository
+}
+
+func NewHandler(repos repository.Repositories) *Handler {
+	return &Handler{
+		articles: repos.Articles,
+		tags:     repos.Tags,
+	}
+}
@@ -0,0 +1,172 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CompleteUploadInput struct {
+	Key          string `json:"key"`
+	OriginalName string `json:"originalName"`
+}
+
+// CompleteUpload confirms a presigned upload of the same API key. It
+// checks the object landed in the bucket, validates it is an image by
+// its magic bytes, strips its metadata like UploadFiles does, puts it
+// back and records it
+func (h *Handler) CompleteUpload(c *fiber.Ctx) error {
+	imageProcessor := pkg.ImageProcessor{}
+	apiKeyName, _ := c.Locals("apiKeyName").(string)
+
+	input := CompleteUploadInput{}
+	if err := c.BodyParser(&input); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if !strings.HasPrefix(input.Key, constants.UPLOAD_PRESIGN_KEY_PREFIX) ||
+		strings.Contains(input.Key, "..") {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid upload key!")
+	}
+
+	savedFileRecord, err := h.fileRecords.FindByFilename(input.Key)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return err
+	}
+	if savedFileRecord.ID != "" && savedFileRecord.UploadedBy == apiKeyName {
+		return fiber.NewError(fiber.StatusConflict, "Upload is already completed!")
+	}
+
+	pendingUpload, err := h.pendingUploads.FindByKey(input.Key)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return err
+	}
+	// Keys presigned for another API key are reported like unknown ones
+	if err != nil || pendingUpload.UploadedBy != apiKeyName || time.Now().After(pendingUpload.ExpiresAt) {
+		return fiber.NewError(fiber.StatusNotFound, "No pending upload found for the given key!")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	newS3Client, err := getS3Client(ctx)
+	if err != nil {
+		log.Printf("Error creating newS3Client: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to reach the file storage!")
+	}
+
+	exists, err := newS3Client.FileExists(ctx, input.Key)
+	if err != nil {
+		log.Printf("Error checking upload %s: %v", input.Key, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to reach the file storage!")
+	}
+	if !exists {
+		return fiber.NewError(fiber.StatusNotFound, "No uploaded file found for the given key!")
+	}
+
+	data, err := newS3Client.DownloadFile(ctx, input.Key, constants.UPLOAD_MAX_FILE_SIZE)
+	if err != nil {
+		log.Printf("Error downloading upload %s: %v", input.Key, err)
+		h.deleteRejectedUpload(ctx, newS3Client, input.Key)
+		return fiber.NewError(fiber.StatusUnprocessableEntity,
+			fmt.Sprintf("Uploaded file couldn't be read, it must be at most %d bytes!", constants.UPLOAD_MAX_FILE_SIZE))
+	}
+
+	contentType, err := imageProcessor.GetContentTypeFromBinary(data)
+	if err != nil || !allowedUploadContentTypes[contentType] {
+		h.deleteRejectedUpload(ctx, newS3Client, input.Key)
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Uploaded file is not a supported image!")
+	}
+
+	data, err = imageProcessor.StripMetadata(data, contentType)
+	if err != nil {
+		h.deleteRejectedUpload(ctx, newS3Client, input.Key)
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	size := int64(len(data))
+	if err := h.quota.Reserve(ctx, apiKeyName, size); err != nil {
+		if !errors.Is(err, errQuotaExceeded) {
+			log.Printf("Error reserving the upload quota of %s: %v", apiKeyName, err)
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to check the upload quota!")
+		}
+		h.deleteRejectedUpload(ctx, newS3Client, input.Key)
+		return fiber.NewError(fiber.StatusTooManyRequests, err.Error())
+	}
+
+	if err := newS3Client.PutFile(ctx, imageProcessor.BinaryToReader(data), input.Key, contentType); err != nil {
+		log.Printf("Error putting stripped upload %s: %v", input.Key, err)
+		h.quota.Release(ctx, apiKeyName, size)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to store the uploaded file!")
+	}
+
+	newFileRecord := models.FileRecord{
+		URL:          newS3Client.ObjectURL(input.Key),
+		Filename:     input.Key,
+		OriginalName: input.OriginalName,
+		Size:         size,
+		ContentType:  contentType,
+		UploadedBy:   apiKeyName,
+	}
+
+	imageMetadata, err := imageProcessor.GetImageMetadata(data)
+	if err != nil {
+		log.Printf("Error getting image metadata for %s: %v", input.Key, err)
+	}
+	if err == nil {
+		newFileRecord.Width = imageMetadata.Width
+		newFileRecord.Height = imageMetadata.Height
+		newFileRecord.DominantColor = imageMetadata.DominantColor
+		newFileRecord.BlurHash = imageMetadata.BlurHash
+	}
+
+	// Deleting the pending upload claims it before it's recorded, so the
+	// sweeper never deletes the object of a completed upload and only
+	// one of two concurrent completions goes on
+	if err := h.pendingUploads.Delete(input.Key); err != nil {
+		h.quota.Release(ctx, apiKeyName, size)
+		if errors.Is(err, models.ErrNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "No pending upload found for the given key!")
+		}
+		return err
+	}
+
+	createdFileRecord, err := h.fileRecords.Create(newFileRecord)
+	if err != nil {
+		h.quota.Release(ctx, apiKeyName, size)
+		// Put the pending upload back so it can be completed again, or
+		// expires with its object
+		if _, restoreErr := h.pendingUploads.Create(pendingUpload); restoreErr != nil {
+			log.Printf("Error restoring pending upload %s: %v", input.Key, restoreErr)
+		}
+		return err
+	}
+
+	response := fiber.Map{
+		"status":  "success",
+		"message": "Upload completed successfully",
+		"data":    createdFileRecord,
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// deleteRejectedUpload removes the object and its pending upload, the
+// key can't be completed anymore
+func (h *Handler) deleteRejectedUpload(ctx context.Context, s3Client *pkg.S3Client, key string) {
+	if err := s3Client.DeleteFile(ctx, key); err != nil {
+		log.Printf("Error deleting rejected upload %s: %v", key, err)
+		return
+	}
+	if err := h.pendingUploads.Delete(key); err != nil {
+		log.Printf("Error deleting pending upload %s: %v", key, err)
+	}
+}
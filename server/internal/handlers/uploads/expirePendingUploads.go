@@ -0,0 +1,59 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+)
+
+// ExpirePendingUploads deletes presigned uploads that were never
+// completed, object and row, every UPLOAD_PENDING_SWEEP_INTERVAL.
+// Replicas may sweep at the same time, a row another one deleted
+// first is skipped
+func (h *Handler) ExpirePendingUploads() {
+	ticker := time.NewTicker(constants.UPLOAD_PENDING_SWEEP_INTERVAL)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := h.deleteExpiredUploads(); err != nil {
+			log.Printf("Error expiring pending uploads: %v", err)
+		}
+	}
+}
+
+func (h *Handler) deleteExpiredUploads() error {
+	const batchSize = 100
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	for {
+		pendingUploads, err := h.pendingUploads.FindExpired(time.Now(), batchSize)
+		if err != nil || len(pendingUploads) == 0 {
+			return err
+		}
+
+		newS3Client, err := getS3Client(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, pendingUpload := range pendingUploads {
+			if err := newS3Client.DeleteFile(ctx, pendingUpload.Key); err != nil {
+				return err
+			}
+			err := h.pendingUploads.Delete(pendingUpload.Key)
+			if err != nil && !errors.Is(err, models.ErrNotFound) {
+				return err
+			}
+			log.Printf("Deleted expired upload %s of %s", pendingUpload.Key, pendingUpload.UploadedBy)
+		}
+		if len(pendingUploads) < batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package uploads
+
+import (
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/cache"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// Handler serves the upload routes against the injected repositories,
+// the upload quota is counted in c
+type Handler struct {
+	fileRecords    repository.FileRecordRepository
+	pendingUploads repository.PendingUploadRepository
+	quota          *UploadQuota
+}
+
+func NewHandler(repos repository.Repositories, c cache.Cache) *Handler {
+	return &Handler{
+		fileRecords:    repos.FileRecords,
+		pendingUploads: repos.PendingUploads,
+		quota:          NewUploadQuota(c),
+	}
+}
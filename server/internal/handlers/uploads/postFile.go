@@ -2,84 +2,178 @@ package uploads
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"mime/multipart"
+	"sync"
 	"time"
 
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
 	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
 	"github.com/gofiber/fiber/v2"
 )
 
-func UploadFiles(c *fiber.Ctx) error {
+type UploadError struct {
+	Filename string `json:"filename"`
+	Message  string `json:"message"`
+}
+
+// allowedUploadContentTypes are the image types uploads may have, by
+// their magic bytes. SVG could carry scripts and TIFF keeps its
+// metadata, so neither is accepted
+var allowedUploadContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+var (
+	s3Client      *pkg.S3Client
+	s3ClientMutex sync.Mutex
+)
+
+// getS3Client returns a client shared by all upload requests,
+// retrying creation on the next call if it previously failed
+func getS3Client(ctx context.Context) (*pkg.S3Client, error) {
+	s3ClientMutex.Lock()
+	defer s3ClientMutex.Unlock()
+
+	if s3Client != nil {
+		return s3Client, nil
+	}
+
+	newS3Client, err := (&pkg.S3Client{}).NewS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s3Client = newS3Client
+
+	return s3Client, nil
+}
+
+// readUploadedFile reads at most UPLOAD_MAX_FILE_SIZE bytes of the file
+func readUploadedFile(file *multipart.FileHeader) ([]byte, error) {
+	if file.Size > constants.UPLOAD_MAX_FILE_SIZE {
+		return nil, fmt.Errorf("file exceeds the maximum size of %d bytes", constants.UPLOAD_MAX_FILE_SIZE)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, constants.UPLOAD_MAX_FILE_SIZE+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(data)) > constants.UPLOAD_MAX_FILE_SIZE {
+		return nil, fmt.Errorf("file exceeds the maximum size of %d bytes", constants.UPLOAD_MAX_FILE_SIZE)
+	}
+
+	return data, nil
+}
+
+func (h *Handler) UploadFiles(c *fiber.Ctx) error {
+	apiKeyName, _ := c.Locals("apiKeyName").(string)
+	imageProcessor := pkg.ImageProcessor{}
+
 	// Parse multipart form
 	form, err := c.MultipartForm()
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid multipart form",
-			"message": err.Error(),
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid multipart form!")
 	}
 
 	files := form.File["files"]
 	if len(files) == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "No files provided",
-			"message": "Please upload at least one file with field name 'files'",
-		})
+		return fiber.NewError(fiber.StatusBadRequest,
+			"Please upload at least one file with field name 'files'")
+	}
+	if len(files) > constants.UPLOAD_MAX_FILES_PER_REQUEST {
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("Too many files! At most %d files can be uploaded at once", constants.UPLOAD_MAX_FILES_PER_REQUEST))
 	}
-
-	var uploadResponses []pkg.UploadResponse
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
+	newS3Client, err := getS3Client(ctx)
+	if err != nil {
+		log.Printf("Error creating newS3Client: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to reach the file storage!")
+	}
+
+	uploadResponses := []pkg.UploadResponse{}
+	uploadErrors := []UploadError{}
+
 	for _, file := range files {
-		// Open the file
-		src, err := file.Open()
+		data, err := readUploadedFile(file)
 		if err != nil {
-			log.Printf("Error opening file %s: %v", file.Filename, err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Failed to open file",
-				"message": err.Error(),
-			})
+			uploadErrors = append(uploadErrors, UploadError{Filename: file.Filename, Message: err.Error()})
+			continue
 		}
 
-		// Upload to S3
-		s3Client := pkg.S3Client{}
+		// Trust the file's magic bytes, not the client supplied Content-Type
+		contentType, err := imageProcessor.GetContentTypeFromBinary(data)
+		if err != nil || !allowedUploadContentTypes[contentType] {
+			uploadErrors = append(uploadErrors, UploadError{Filename: file.Filename,
+				Message: "File is not a supported image, upload a JPEG, PNG, GIF or WebP"})
+			continue
+		}
 
-		newS3Client, err := s3Client.NewS3Client(ctx)
+		data, err = imageProcessor.StripMetadata(data, contentType)
 		if err != nil {
-			log.Printf("Error creating newS3Client: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Failed to upload file to S3",
-				"message": err.Error(),
-			})
+			uploadErrors = append(uploadErrors, UploadError{Filename: file.Filename, Message: err.Error()})
+			continue
+		}
+		size := int64(len(data))
+
+		if err := h.quota.Reserve(ctx, apiKeyName, size); err != nil {
+			message := err.Error()
+			if !errors.Is(err, errQuotaExceeded) {
+				log.Printf("Error reserving the upload quota of %s: %v", apiKeyName, err)
+				message = "Failed to check the upload quota"
+			}
+			uploadErrors = append(uploadErrors, UploadError{Filename: file.Filename, Message: message})
+			continue
 		}
 
 		uploadResp, err := newS3Client.UploadFile(
 			ctx,
-			src,
+			imageProcessor.BinaryToReader(data),
 			file.Filename,
-			file.Header.Get("Content-Type"),
-			file.Size,
+			contentType,
+			size,
 		)
-		src.Close()
-
 		if err != nil {
 			log.Printf("Error uploading file %s to S3: %v", file.Filename, err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Failed to upload file to S3",
-				"message": err.Error(),
-			})
+			h.quota.Release(ctx, apiKeyName, size)
+			uploadErrors = append(uploadErrors, UploadError{Filename: file.Filename, Message: "Failed to upload file"})
+			continue
 		}
 
 		uploadResponses = append(uploadResponses, *uploadResp)
 	}
 
-	// Return success response
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"success": true,
-		"message": "Files uploaded successfully",
+	statusCode := fiber.StatusCreated
+	message := "Files uploaded successfully"
+	if len(uploadErrors) > 0 && len(uploadResponses) > 0 {
+		statusCode = fiber.StatusMultiStatus
+		message = "Some files failed to upload"
+	}
+	if len(uploadResponses) == 0 {
+		statusCode = fiber.StatusUnprocessableEntity
+		message = "No file was uploaded"
+	}
+
+	return c.Status(statusCode).JSON(fiber.Map{
+		"success": len(uploadResponses) > 0,
+		"message": message,
 		"files":   uploadResponses,
+		"errors":  uploadErrors,
 		"count":   len(uploadResponses),
 	})
 }
@@ -0,0 +1,86 @@
+package uploads
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PresignUploadInput struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+}
+
+// PresignUpload returns a presigned PUT URL so the client can send
+// the file straight to the bucket. The upload must then be confirmed
+// through CompleteUpload with the same API key before it is recorded,
+// uploads left pending past UPLOAD_PENDING_EXPIRY are deleted
+func (h *Handler) PresignUpload(c *fiber.Ctx) error {
+	apiKeyName, _ := c.Locals("apiKeyName").(string)
+
+	input := PresignUploadInput{}
+	if err := c.BodyParser(&input); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if input.Filename == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Please provide the filename!")
+	}
+	if !allowedUploadContentTypes[input.ContentType] {
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("Unsupported content type '%s'!", input.ContentType))
+	}
+	if input.Size <= 0 || input.Size > constants.UPLOAD_MAX_FILE_SIZE {
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("File size must be between 1 and %d bytes!", constants.UPLOAD_MAX_FILE_SIZE))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	newS3Client, err := getS3Client(ctx)
+	if err != nil {
+		log.Printf("Error creating newS3Client: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to reach the file storage!")
+	}
+
+	key := newS3Client.NewObjectKey(constants.UPLOAD_PRESIGN_KEY_PREFIX, input.Filename)
+
+	uploadURL, err := newS3Client.GetPresignedUploadURL(ctx, key, input.ContentType,
+		input.Size, constants.UPLOAD_PRESIGN_EXPIRY)
+	if err != nil {
+		log.Printf("Error presigning upload %s: %v", key, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to presign the upload!")
+	}
+
+	_, err = h.pendingUploads.Create(models.PendingUpload{
+		Key:         key,
+		UploadedBy:  apiKeyName,
+		ContentType: input.ContentType,
+		ExpiresAt:   time.Now().Add(constants.UPLOAD_PENDING_EXPIRY),
+	})
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"uploadURL": uploadURL,
+			"method":    fiber.MethodPut,
+			"key":       key,
+			"headers": fiber.Map{
+				fiber.HeaderContentType: input.ContentType,
+			},
+			"expiresAt": time.Now().Add(constants.UPLOAD_PRESIGN_EXPIRY),
+		},
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,78 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/cache"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+)
+
+// errQuotaExceeded is wrapped by Reserve when an API key is out of
+// quota, other errors come from the cache
+var errQuotaExceeded = errors.New("upload quota exceeded")
+
+// UploadQuota counts the files and bytes each API key uploaded within
+// the window in the cache, so replicas sharing a Redis enforce one
+// quota between them and restarts don't reset it. The window starts
+// with the first upload of a key
+type UploadQuota struct {
+	cache    cache.Cache
+	maxBytes int64
+	maxFiles int64
+	window   time.Duration
+}
+
+func NewUploadQuota(c cache.Cache) *UploadQuota {
+	return &UploadQuota{
+		cache:    c,
+		maxBytes: constants.UPLOAD_DAILY_BYTES_QUOTA,
+		maxFiles: int64(constants.UPLOAD_DAILY_FILES_QUOTA),
+		window:   24 * time.Hour,
+	}
+}
+
+// Reserve books size bytes and one file against the key's quota,
+// returning an error wrapping errQuotaExceeded when either limit would
+// be exceeded
+func (uq *UploadQuota) Reserve(ctx context.Context, key string, size int64) error {
+	files, err := uq.cache.IncrBy(ctx, "uploadquota:files:"+key, 1, uq.window)
+	if err != nil {
+		return err
+	}
+	bytes, err := uq.cache.IncrBy(ctx, "uploadquota:bytes:"+key, size, uq.window)
+	if err != nil {
+		uq.release(ctx, key, 1, 0)
+		return err
+	}
+
+	if files > uq.maxFiles {
+		uq.release(ctx, key, 1, size)
+		return fmt.Errorf("%w, at most %d files a day", errQuotaExceeded, uq.maxFiles)
+	}
+	if bytes > uq.maxBytes {
+		uq.release(ctx, key, 1, size)
+		return fmt.Errorf("%w, at most %d bytes a day", errQuotaExceeded, uq.maxBytes)
+	}
+	return nil
+}
+
+// Release gives back a reservation for an upload that failed
+func (uq *UploadQuota) Release(ctx context.Context, key string, size int64) {
+	uq.release(ctx, key, 1, size)
+}
+
+func (uq *UploadQuota) release(ctx context.Context, key string, files, size int64) {
+	if _, err := uq.cache.IncrBy(ctx, "uploadquota:files:"+key, -files, uq.window); err != nil {
+		log.Printf("Error releasing the file quota of %s: %v", key, err)
+	}
+	if size == 0 {
+		return
+	}
+	if _, err := uq.cache.IncrBy(ctx, "uploadquota:bytes:"+key, -size, uq.window); err != nil {
+		log.Printf("Error releasing the byte quota of %s: %v", key, err)
+	}
+}
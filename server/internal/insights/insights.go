@@ -0,0 +1,263 @@
+// Package insights describes the publishing rhythm of the index:
+// streaks of days with articles, the busiest weekday and month, gaps
+// without articles, the top authors of every period and how years
+// compare. Everything is computed from the day counts of every author
+package insights
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+type Options struct {
+	// Today is the current calendar day in the zone the day counts
+	// were bucketed in, it ends the current streak
+	Today time.Time
+	// MinGapDays lists the gaps of more than that many days
+	MinGapDays int
+	// Period is repository.IntervalMonth or repository.IntervalYear
+	Period     string
+	TopAuthors int
+}
+
+// Streak is a run of consecutive days with at least one article
+type Streak struct {
+	Days int    `json:"days"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Gap is a run of days without articles between two active days
+type Gap struct {
+	Days int    `json:"days"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type NamedCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type AuthorCount struct {
+	AuthorID string `json:"authorID"`
+	Name     string `json:"name"`
+	Count    int64  `json:"count"`
+}
+
+type PeriodAuthors struct {
+	Period  string        `json:"period"`
+	Total   int64         `json:"total"`
+	Authors []AuthorCount `json:"authors"`
+}
+
+// YearComparison compares a year with the one before. ToDate counts
+// the articles up to the day and month of today, the current year is
+// compared on ToDate so it isn't measured against a full year
+type YearComparison struct {
+	Year          int      `json:"year"`
+	Count         int64    `json:"count"`
+	ToDate        int64    `json:"toDate"`
+	Change        int64    `json:"change"`
+	ChangePercent *float64 `json:"changePercent"`
+}
+
+type Insights struct {
+	TotalArticles  int64            `json:"totalArticles"`
+	ActiveDays     int              `json:"activeDays"`
+	FirstDay       string           `json:"firstDay,omitempty"`
+	LastDay        string           `json:"lastDay,omitempty"`
+	LongestStreak  Streak           `json:"longestStreak"`
+	CurrentStreak  Streak           `json:"currentStreak"`
+	BusiestWeekday *NamedCount      `json:"busiestWeekday"`
+	BusiestMonth   *NamedCount      `json:"busiestMonth"`
+	Weekdays       []NamedCount     `json:"weekdays"`
+	Months         []NamedCount     `json:"months"`
+	Gaps           []Gap            `json:"gaps"`
+	TopAuthors     []PeriodAuthors  `json:"topAuthors"`
+	YearOverYear   []YearComparison `json:"yearOverYear"`
+}
+
+// Compute derives the insights from day counts ordered by day
+func Compute(dayCounts []repository.AuthorDayCount, options Options) Insights {
+	insights := Insights{
+		Weekdays:     make([]NamedCount, 7),
+		Months:       make([]NamedCount, 12),
+		Gaps:         []Gap{},
+		TopAuthors:   []PeriodAuthors{},
+		YearOverYear: []YearComparison{},
+	}
+	// Weeks start on Monday like the timeseries buckets
+	for i := range insights.Weekdays {
+		insights.Weekdays[i].Name = time.Weekday((i + 1) % 7).String()
+	}
+	for i := range insights.Months {
+		insights.Months[i].Name = time.Month(i + 1).String()
+	}
+
+	var days []time.Time
+	for _, dayCount := range dayCounts {
+		insights.TotalArticles += dayCount.Count
+		insights.Weekdays[(int(dayCount.Day.Weekday())+6)%7].Count += dayCount.Count
+		insights.Months[dayCount.Day.Month()-1].Count += dayCount.Count
+		if len(days) == 0 || !days[len(days)-1].Equal(dayCount.Day) {
+			days = append(days, dayCount.Day)
+		}
+	}
+	if len(days) == 0 {
+		return insights
+	}
+
+	insights.ActiveDays = len(days)
+	insights.FirstDay = days[0].Format(time.DateOnly)
+	insights.LastDay = days[len(days)-1].Format(time.DateOnly)
+	insights.BusiestWeekday = busiest(insights.Weekdays)
+	insights.BusiestMonth = busiest(insights.Months)
+
+	streakStart := days[0]
+	for i, day := range days {
+		if i > 0 {
+			gapDays := int(day.Sub(days[i-1]).Hours()/24) - 1
+			if gapDays > 0 {
+				streakStart = day
+			}
+			if gapDays > options.MinGapDays {
+				insights.Gaps = append(insights.Gaps, Gap{
+					Days: gapDays,
+					From: days[i-1].AddDate(0, 0, 1).Format(time.DateOnly),
+					To:   day.AddDate(0, 0, -1).Format(time.DateOnly),
+				})
+			}
+		}
+
+		streak := newStreak(streakStart, day)
+		if streak.Days > insights.LongestStreak.Days {
+			insights.LongestStreak = streak
+		}
+	}
+
+	// The current streak survives until a full day passes without an
+	// article, today may still get one
+	lastDay := days[len(days)-1]
+	if !lastDay.Before(options.Today.AddDate(0, 0, -1)) {
+		insights.CurrentStreak = newStreak(streakStart, lastDay)
+	}
+
+	insights.TopAuthors = topAuthors(dayCounts, options.Period, options.TopAuthors)
+	insights.YearOverYear = yearOverYear(dayCounts, options.Today)
+
+	return insights
+}
+
+func newStreak(from, to time.Time) Streak {
+	return Streak{
+		Days: int(to.Sub(from).Hours()/24) + 1,
+		From: from.Format(time.DateOnly),
+		To:   to.Format(time.DateOnly),
+	}
+}
+
+// busiest returns the first of the names with the most articles
+func busiest(counts []NamedCount) *NamedCount {
+	top := counts[0]
+	for _, count := range counts[1:] {
+		if count.Count > top.Count {
+			top = count
+		}
+	}
+	return &top
+}
+
+// topAuthors ranks the authors of every period, latest period first
+func topAuthors(dayCounts []repository.AuthorDayCount, period string, limit int) []PeriodAuthors {
+	format := "2006-01"
+	if period == repository.IntervalYear {
+		format = "2006"
+	}
+
+	type periodAuthor struct {
+		period   string
+		authorID string
+	}
+	counts := make(map[periodAuthor]*AuthorCount)
+	periods := make(map[string]*PeriodAuthors)
+	var order []string
+
+	for _, dayCount := range dayCounts {
+		key := repository.TruncateDate(dayCount.Day, period).Format(format)
+		if _, ok := periods[key]; !ok {
+			periods[key] = &PeriodAuthors{Period: key}
+			order = append(order, key)
+		}
+		periods[key].Total += dayCount.Count
+
+		author, ok := counts[periodAuthor{key, dayCount.AuthorID}]
+		if !ok {
+			author = &AuthorCount{AuthorID: dayCount.AuthorID, Name: dayCount.AuthorName}
+			counts[periodAuthor{key, dayCount.AuthorID}] = author
+		}
+		author.Count += dayCount.Count
+	}
+
+	for key, author := range counts {
+		periods[key.period].Authors = append(periods[key.period].Authors, *author)
+	}
+
+	top := make([]PeriodAuthors, 0, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		periodAuthors := periods[order[i]]
+		sort.Slice(periodAuthors.Authors, func(a, b int) bool {
+			authors := periodAuthors.Authors
+			if authors[a].Count != authors[b].Count {
+				return authors[a].Count > authors[b].Count
+			}
+			return authors[a].Name < authors[b].Name
+		})
+		if len(periodAuthors.Authors) > limit {
+			periodAuthors.Authors = periodAuthors.Authors[:limit]
+		}
+		top = append(top, *periodAuthors)
+	}
+	return top
+}
+
+// yearOverYear compares every year since the first article to the
+// year before, latest year first
+func yearOverYear(dayCounts []repository.AuthorDayCount, today time.Time) []YearComparison {
+	firstYear, lastYear := dayCounts[0].Day.Year(), dayCounts[len(dayCounts)-1].Day.Year()
+	years := make([]YearComparison, lastYear-firstYear+1)
+
+	for i := range years {
+		years[i].Year = firstYear + i
+	}
+	for _, dayCount := range dayCounts {
+		year := &years[dayCount.Day.Year()-firstYear]
+		year.Count += dayCount.Count
+
+		// Compared by month and day so leap years line up
+		if dayCount.Day.Month() < today.Month() ||
+			(dayCount.Day.Month() == today.Month() && dayCount.Day.Day() <= today.Day()) {
+			year.ToDate += dayCount.Count
+		}
+	}
+
+	comparisons := make([]YearComparison, 0, len(years))
+	for i := len(years) - 1; i >= 0; i-- {
+		year := years[i]
+		if i > 0 {
+			count, previousCount := year.Count, years[i-1].Count
+			if year.Year == today.Year() {
+				count, previousCount = year.ToDate, years[i-1].ToDate
+			}
+			year.Change = count - previousCount
+			if previousCount > 0 {
+				changePercent := float64(year.Change) / float64(previousCount) * 100
+				year.ChangePercent = &changePercent
+			}
+		}
+		comparisons = append(comparisons, year)
+	}
+	return comparisons
+}
@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyStore struct {
+	keys map[[32]byte]string
+	once sync.Once
+}
+
+var apiKeyStore = &APIKeyStore{}
+
+// load reads the comma separated API_KEYS env var. Each entry is
+// either a bare key or "name:key" so logs can refer to the owner
+// without printing the secret
+func (ks *APIKeyStore) load() {
+	ks.keys = make(map[[32]byte]string)
+
+	for index, entry := range strings.Split(os.Getenv("API_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, key, found := strings.Cut(entry, ":")
+		if !found {
+			key = name
+			name = fmt.Sprintf("key-%d", index+1)
+		}
+		ks.keys[sha256.Sum256([]byte(key))] = name
+	}
+	log.Printf("Loaded %d API keys", len(ks.keys))
+}
+
+// Lookup returns the owner name of the key. Keys are stored and
+// looked up by their SHA-256 digest so the comparison doesn't leak
+// how much of a guessed key matched
+func (ks *APIKeyStore) Lookup(key string) (string, bool) {
+	ks.once.Do(ks.load)
+
+	name, ok := ks.keys[sha256.Sum256([]byte(key))]
+	return name, ok
+}
+
+func apiKeyFromRequest(c *fiber.Ctx) string {
+	if key := c.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	authorization := c.Get(fiber.HeaderAuthorization)
+	if token, found := strings.CutPrefix(authorization, "Bearer "); found {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// RequireAPIKey rejects requests without a valid key in the
+// X-API-Key header or an "Authorization: Bearer <key>" header
+func RequireAPIKey(c *fiber.Ctx) error {
+	key := apiKeyFromRequest(c)
+	if key == "" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Missing API key!")
+	}
+
+	name, ok := apiKeyStore.Lookup(key)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid API key!")
+	}
+
+	c.Locals("apiKey", key)
+	c.Locals("apiKeyName", name)
+
+	return c.Next()
+}
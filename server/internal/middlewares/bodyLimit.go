@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LimitRequestBody rejects requests whose body is above maxBytes, or
+// above the limit of their path in routeLimits. The server streams
+// bodies beyond its BodyLimit instead of refusing them, so this is
+// what keeps them out of the handlers. A Content-Length is checked up
+// front, bodies of unknown length e.g chunked ones are read up to the
+// limit and handed on once complete. The connection is closed after a
+// refusal since the body is left unread
+func LimitRequestBody(maxBytes int, routeLimits map[string]int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := maxBytes
+		if routeLimit, ok := routeLimits[strings.TrimSuffix(c.Path(), "/")]; ok {
+			limit = routeLimit
+		}
+
+		contentLength := c.Request().Header.ContentLength()
+		if contentLength > limit {
+			return refuseRequestBody(c, limit)
+		}
+
+		stream := c.Request().BodyStream()
+		if contentLength == -1 && stream != nil {
+			body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+			if err != nil {
+				c.Context().SetConnectionClose()
+				return fiber.NewError(fiber.StatusBadRequest, "Failed to read the request body!")
+			}
+			if len(body) > limit {
+				return refuseRequestBody(c, limit)
+			}
+			c.Request().SetBody(body)
+			c.Request().Header.SetContentLength(len(body))
+		}
+		return c.Next()
+	}
+}
+
+func refuseRequestBody(c *fiber.Ctx, limit int) error {
+	c.Context().SetConnectionClose()
+	return fiber.NewError(fiber.StatusRequestEntityTooLarge,
+		fmt.Sprintf("Request body exceeds the maximum size of %d bytes!", limit))
+}
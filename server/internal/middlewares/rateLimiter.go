@@ -1,92 +1,73 @@
 package middlewares
 
 import (
+	"context"
 	"log"
 	"os"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/cache"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
 	"github.com/gofiber/fiber/v2"
 )
 
+// RateLimiter counts the requests of every client ip in the cache, so
+// replicas sharing a Redis enforce one limit between them. While the
+// cache is down every process counts on its own in fallback
 type RateLimiter struct {
-	requests     map[string]int
-	blockedUntil map[string]time.Time
-	resetAfter   map[string]time.Time
-	limit        int
-	window       time.Duration
-	mutex        sync.Mutex
+	cache    cache.Cache
+	fallback cache.Cache
+	limit    int
+	window   time.Duration
 }
 
-var rateLimiter = &RateLimiter{
-	requests:     make(map[string]int),
-	blockedUntil: make(map[string]time.Time),
-	resetAfter:   make(map[string]time.Time),
-	window:       60 * time.Second, // 1 min window
-	limit:        20,
+// NewRateLimiter counts in c, which shouldn't be an in-memory cache
+// other entries could fill, evicting the counters
+func NewRateLimiter(c cache.Cache) *RateLimiter {
+	return &RateLimiter{
+		cache:    c,
+		fallback: cache.NewMemory(),
+		limit:    constants.RATE_LIMIT_REQUESTS,
+		window:   constants.RATE_LIMIT_WINDOW,
+	}
 }
 
-// AllowRequest blocks requests until the window expires
-func (rl *RateLimiter) AllowRequest(clientIp string) bool {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
+// AllowRequest blocks requests until the window expires. A client
+// going over the limit is blocked for a whole window from then on
+func (rl *RateLimiter) AllowRequest(ctx context.Context, clientIp string) (bool, error) {
+	allowed, err := rl.allow(ctx, rl.cache, clientIp)
+	if err != nil {
+		log.Println("Rate limiter error, counting in this process:", err)
+		return rl.allow(ctx, rl.fallback, clientIp)
+	}
+	return allowed, nil
+}
 
-	if unblockTime, blocked := rl.blockedUntil[clientIp]; blocked {
-		if time.Now().Before(unblockTime) {
-			return false
-		}
+func (rl *RateLimiter) allow(ctx context.Context, c cache.Cache, clientIp string) (bool, error) {
+	blockedKey := "ratelimit:blocked:" + clientIp
 
-		delete(rl.blockedUntil, clientIp)
+	_, blocked, err := c.Get(ctx, blockedKey)
+	if err != nil || blocked {
+		return false, err
 	}
 
-	if resetTime, exists := rl.resetAfter[clientIp]; exists && time.Now().After(resetTime) {
-		delete(rl.requests, clientIp)
-		delete(rl.resetAfter, clientIp)
+	count, err := c.Incr(ctx, "ratelimit:requests:"+clientIp, rl.window)
+	if err != nil {
+		return false, err
 	}
-
-	count := rl.requests[clientIp]
-	if count <= rl.limit {
-		if count == 0 {
-			rl.resetAfter[clientIp] = time.Now().Add(rl.window)
-		}
-		rl.requests[clientIp]++
-		return true
+	if count <= int64(rl.limit) {
+		return true, nil
 	}
 
 	// Block further requests
-	rl.blockedUntil[clientIp] = time.Now().Add(rl.window)
-	return false
-}
-
-// CleanupExpiredEntries removes expired IPs periodically
-func (rl *RateLimiter) CleanupExpiredEntries() {
-	log.Println("IP cleanup initiated...")
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		rl.mutex.Lock()
-		now := time.Now()
-
-		for ip, resetTime := range rl.resetAfter {
-			if now.After(resetTime) {
-				delete(rl.requests, ip)
-				delete(rl.resetAfter, ip)
-			}
-		}
-
-		for ip, unblockTime := range rl.blockedUntil {
-			if now.After(unblockTime) {
-				delete(rl.blockedUntil, ip)
-			}
-		}
-
-		rl.mutex.Unlock()
+	if err := c.Set(ctx, blockedKey, []byte("1"), rl.window); err != nil {
+		log.Println("Error blocking", clientIp, err)
 	}
+	return false, nil
 }
 
-func RateLimit(c *fiber.Ctx) error {
+func (rl *RateLimiter) Limit(c *fiber.Ctx) error {
 	var clientIP string = ""
 
 	if os.Getenv("GO_ENV") == "production" {
@@ -114,7 +95,11 @@ func RateLimit(c *fiber.Ctx) error {
 
 	log.Println("clientIP address:", clientIP)
 
-	if !rateLimiter.AllowRequest(clientIP) {
+	allowed, err := rl.AllowRequest(c.Context(), clientIP)
+	if err != nil {
+		return err
+	}
+	if !allowed {
 		log.Println("Request denied for:", clientIP)
 		return fiber.NewError(fiber.StatusTooManyRequests,
 			"You have made too many requests! Please try again later.")
@@ -124,7 +109,3 @@ func RateLimit(c *fiber.Ctx) error {
 
 	return c.Next()
 }
-
-func init() {
-	go rateLimiter.CleanupExpiredEntries()
-}
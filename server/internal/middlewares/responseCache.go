@@ -0,0 +1,202 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/cache"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/events"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/pkg"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResponseCache keeps the successful GET responses of the read routes
+// until the articles change. Every entry is dropped at once when an
+// article is saved or a CLI import or restore calls
+// InvalidateResponses, and after RESPONSE_CACHE_TTL either way since
+// clicks don't publish events. The in-memory backend only sees the
+// events of its own process, CLI imports and restores can't reach it
+// so its entries last until the ttl or a restart
+type ResponseCache struct {
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+type cachedResponse struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"contentType"`
+	CacheControl string    `json:"cacheControl"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// responseGenerationKey counts the invalidations. Entries are stored
+// under the generation they were rendered in, so bumping it drops
+// them all in every replica and the old ones expire on their own
+const responseGenerationKey = "responses:generation"
+
+func NewResponseCache(c cache.Cache) *ResponseCache {
+	return &ResponseCache{cache: c, ttl: constants.RESPONSE_CACHE_TTL}
+}
+
+// Generation changes every time the cached responses are invalidated,
+// in every process sharing the cache
+func (rc *ResponseCache) Generation(ctx context.Context) (string, error) {
+	generation, ok, err := rc.cache.Get(ctx, responseGenerationKey)
+	if err != nil || !ok {
+		return "0", err
+	}
+	return string(generation), nil
+}
+
+func (rc *ResponseCache) get(ctx context.Context, key string) (cachedResponse, string, bool, error) {
+	var response cachedResponse
+
+	generation, err := rc.Generation(ctx)
+	if err != nil {
+		return response, generation, false, err
+	}
+	data, ok, err := rc.cache.Get(ctx, "responses:"+generation+":"+key)
+	if err != nil || !ok {
+		return response, generation, false, err
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return response, generation, false, err
+	}
+	return response, generation, true, nil
+}
+
+// set stores the response unless the cache was invalidated since it
+// was rendered
+func (rc *ResponseCache) set(ctx context.Context, key string, generation string, response cachedResponse) error {
+	current, err := rc.Generation(ctx)
+	if err != nil || current != generation {
+		return err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return rc.cache.Set(ctx, "responses:"+generation+":"+key, data, rc.ttl)
+}
+
+// Invalidate drops every cached response
+func (rc *ResponseCache) Invalidate(ctx context.Context) error {
+	return InvalidateResponses(ctx, rc.cache)
+}
+
+// InvalidateResponses drops every response cached in c, for processes
+// without a ResponseCache such as the CLI commands. With Redis it
+// reaches every server, the in-memory cache belongs to one process so
+// servers using it need a restart after an offline import or restore
+func InvalidateResponses(ctx context.Context, c cache.Cache) error {
+	_, err := c.Incr(ctx, responseGenerationKey, 0)
+	return err
+}
+
+// InvalidateOnArticleSaved drops the cached responses whenever an
+// article is saved or updated. It subscribes before returning so no
+// event published afterwards is missed
+func (rc *ResponseCache) InvalidateOnArticleSaved() {
+	articleSavedChan := make(chan events.DataEvent)
+	events.EB.Subscribe("ARTICLE_SAVED", articleSavedChan)
+
+	go func() {
+		for range articleSavedChan {
+			if err := rc.Invalidate(context.Background()); err != nil {
+				log.Println("Error invalidating cached responses:", err)
+			}
+		}
+	}()
+}
+
+// responseCacheKey is the path with the query sorted, so the order of
+// the query parameters doesn't matter. The Host is left out since the
+// client controls it, responses linking to it aren't stored
+func responseCacheKey(c *fiber.Ctx) string {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return c.OriginalURL()
+	}
+	return c.Path() + "?" + query.Encode()
+}
+
+// Handle answers GET requests from the response cache and stores the
+// 200 responses of the route it wraps. Responses carry an
+// ETag, computed from the body unless the route sets one, a
+// Last-Modified and a Cache-Control a CDN may follow, and conditional
+// requests get a 304. Requests are served by the route while the cache
+// is down
+func (rc *ResponseCache) Handle(c *fiber.Ctx) error {
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		return c.Next()
+	}
+
+	key := responseCacheKey(c)
+
+	response, generation, ok, err := rc.get(c.Context(), key)
+	if err != nil {
+		log.Println("Error reading cached response:", err)
+		return c.Next()
+	}
+	if ok {
+		c.Set("X-Cache", "HIT")
+		c.Set(fiber.HeaderCacheControl, response.CacheControl)
+		if pkg.NotModified(c, response.ETag, response.LastModified) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+		c.Set(fiber.HeaderContentType, response.ContentType)
+		return c.Send(response.Body)
+	}
+
+	if err := c.Next(); err != nil {
+		return err
+	}
+	// HEAD responses are sent without their body, only GET is stored
+	// and responses linking to the request's Host aren't shared
+	if c.Method() != fiber.MethodGet || c.Response().StatusCode() != fiber.StatusOK ||
+		c.Response().IsBodyStream() || c.Locals(pkg.HostDependentLocal) != nil {
+		return nil
+	}
+
+	body := append([]byte(nil), c.Response().Body()...)
+	response = cachedResponse{
+		Body:         body,
+		ContentType:  string(c.Response().Header.ContentType()),
+		CacheControl: string(c.Response().Header.Peek(fiber.HeaderCacheControl)),
+		ETag:         string(c.Response().Header.Peek(fiber.HeaderETag)),
+	}
+	if response.CacheControl == "" {
+		response.CacheControl = constants.RESPONSE_CACHE_CONTROL
+		c.Set(fiber.HeaderCacheControl, response.CacheControl)
+	}
+	if response.ETag == "" {
+		hash := sha256.Sum256(body)
+		response.ETag = `W/"` + hex.EncodeToString(hash[:])[:32] + `"`
+	}
+	response.LastModified = time.Now()
+	if lastModified := c.Response().Header.Peek(fiber.HeaderLastModified); len(lastModified) > 0 {
+		if parsed, err := http.ParseTime(string(lastModified)); err == nil {
+			response.LastModified = parsed
+		}
+	}
+
+	if err := rc.set(c.Context(), key, generation, response); err != nil {
+		log.Println("Error caching response:", err)
+	}
+	c.Set("X-Cache", "MISS")
+
+	if pkg.NotModified(c, response.ETag, response.LastModified) {
+		c.Status(fiber.StatusNotModified)
+		c.Response().ResetBody()
+	}
+	return nil
+}
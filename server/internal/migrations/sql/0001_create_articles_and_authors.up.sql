@@ -0,0 +1,48 @@
+-- Baseline schema, matches what AutoMigrate created so existing
+-- databases adopt it without changes
+CREATE TABLE IF NOT EXISTS authors (
+    "id" uuid PRIMARY KEY,
+    "name" text NOT NULL,
+    "avatarUrl" text NOT NULL,
+    "avatarFilename" text DEFAULT NULL,
+    "pageUrl" text DEFAULT NULL,
+    "createdAt" timestamptz,
+    "updatedAt" timestamptz,
+    CONSTRAINT "uni_authors_name" UNIQUE ("name")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_authors_name" ON authors ("name");
+CREATE INDEX IF NOT EXISTS "idx_authors_createdAt" ON authors ("createdAt");
+CREATE INDEX IF NOT EXISTS "idx_authors_updatedAt" ON authors ("updatedAt");
+
+CREATE TABLE IF NOT EXISTS articles (
+    "id" uuid PRIMARY KEY,
+    "authorID" uuid NOT NULL,
+    "tag" text NOT NULL,
+    "tagIndex" text,
+    "title" text NOT NULL,
+    "href" text DEFAULT NULL,
+    "imageUrl" text NOT NULL,
+    "imageFilename" text DEFAULT NULL,
+    "postedAt" timestamptz,
+    "readDuration" text,
+    "createdAt" timestamptz,
+    "updatedAt" timestamptz
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_authors_article') THEN
+        ALTER TABLE articles ADD CONSTRAINT "fk_authors_article"
+            FOREIGN KEY ("authorID") REFERENCES authors ("id")
+            ON UPDATE CASCADE ON DELETE CASCADE;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS "idx_articles_authorID" ON articles ("authorID");
+CREATE INDEX IF NOT EXISTS "idx_articles_tag" ON articles ("tag");
+CREATE INDEX IF NOT EXISTS "idx_articles_tagIndex" ON articles ("tagIndex");
+CREATE INDEX IF NOT EXISTS "idx_articles_title" ON articles ("title");
+CREATE INDEX IF NOT EXISTS "idx_articles_postedAt" ON articles ("postedAt");
+CREATE INDEX IF NOT EXISTS "idx_articles_createdAt" ON articles ("createdAt");
+CREATE INDEX IF NOT EXISTS "idx_articles_updatedAt" ON articles ("updatedAt");
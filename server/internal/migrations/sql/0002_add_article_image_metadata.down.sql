@@ -0,0 +1,6 @@
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS "imageWidth",
+    DROP COLUMN IF EXISTS "imageHeight",
+    DROP COLUMN IF EXISTS "imageAspectRatio",
+    DROP COLUMN IF EXISTS "imageDominantColor",
+    DROP COLUMN IF EXISTS "imageBlurHash";
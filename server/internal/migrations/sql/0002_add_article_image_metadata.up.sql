@@ -0,0 +1,6 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS "imageWidth" bigint DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS "imageHeight" bigint DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS "imageAspectRatio" numeric DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS "imageDominantColor" text DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS "imageBlurHash" text DEFAULT NULL;
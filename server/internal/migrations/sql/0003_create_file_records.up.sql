@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS file_records (
+    "id" uuid PRIMARY KEY,
+    "url" text NOT NULL,
+    "filename" text NOT NULL,
+    "originalName" text,
+    "size" bigint,
+    "contentType" text,
+    "width" bigint DEFAULT NULL,
+    "height" bigint DEFAULT NULL,
+    "dominantColor" text DEFAULT NULL,
+    "blurHash" text DEFAULT NULL,
+    "uploadedBy" text,
+    "createdAt" timestamptz,
+    "updatedAt" timestamptz,
+    CONSTRAINT "uni_file_records_filename" UNIQUE ("filename")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_file_records_uploadedBy" ON file_records ("uploadedBy");
+CREATE INDEX IF NOT EXISTS "idx_file_records_createdAt" ON file_records ("createdAt");
+CREATE INDEX IF NOT EXISTS "idx_file_records_updatedAt" ON file_records ("updatedAt");
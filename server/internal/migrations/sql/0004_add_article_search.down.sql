@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS "idx_articles_searchVector";
+
+DROP TRIGGER IF EXISTS authors_search_vector_update ON authors;
+DROP TRIGGER IF EXISTS articles_search_vector_update ON articles;
+
+DROP FUNCTION IF EXISTS authors_search_vector_trigger();
+DROP FUNCTION IF EXISTS articles_search_vector_trigger();
+DROP FUNCTION IF EXISTS article_search_vector(text, text, text, text);
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS "searchVector",
+    DROP COLUMN IF EXISTS "body";
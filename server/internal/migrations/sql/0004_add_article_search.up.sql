@@ -0,0 +1,62 @@
+-- Full-text search over title, tag, author name and the archived
+-- body. A GENERATED column can't read authors.name, so triggers
+-- keep "searchVector" current instead
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS "body" text DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS "searchVector" tsvector;
+
+CREATE OR REPLACE FUNCTION article_search_vector(title text, tag text, author_name text, body text)
+RETURNS tsvector
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+           setweight(to_tsvector('english', coalesce(tag, '')), 'B') ||
+           setweight(to_tsvector('english', coalesce(author_name, '')), 'B') ||
+           setweight(to_tsvector('english', coalesce(body, '')), 'D')
+$$;
+
+CREATE OR REPLACE FUNCTION articles_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    NEW."searchVector" := article_search_vector(
+        NEW.title,
+        NEW.tag,
+        (SELECT name FROM authors WHERE id = NEW."authorID"),
+        NEW.body
+    );
+    RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION authors_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    UPDATE articles
+    SET "searchVector" = article_search_vector(title, tag, NEW.name, body)
+    WHERE "authorID" = NEW.id;
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS articles_search_vector_update ON articles;
+CREATE TRIGGER articles_search_vector_update
+    BEFORE INSERT OR UPDATE OF title, tag, "authorID", body ON articles
+    FOR EACH ROW EXECUTE FUNCTION articles_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS authors_search_vector_update ON authors;
+CREATE TRIGGER authors_search_vector_update
+    AFTER UPDATE OF name ON authors
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION authors_search_vector_trigger();
+
+UPDATE articles
+SET "searchVector" = article_search_vector(articles.title, articles.tag, authors.name, articles.body)
+FROM authors
+WHERE authors.id = articles."authorID";
+
+CREATE INDEX IF NOT EXISTS "idx_articles_searchVector" ON articles USING GIN ("searchVector");
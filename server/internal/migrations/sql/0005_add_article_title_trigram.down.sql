@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_articles_title_trgm";
+
+-- pg_trgm is left installed, other database objects may rely on it
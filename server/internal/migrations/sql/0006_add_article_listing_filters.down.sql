@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "idx_articles_authorID_postedAt";
+DROP INDEX IF EXISTS "idx_articles_sourceTag";
+DROP INDEX IF EXISTS "idx_articles_readDurationMinutes";
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS "sourceTag",
+    DROP COLUMN IF EXISTS "readDurationMinutes";
+
+DROP FUNCTION IF EXISTS read_duration_minutes(text);
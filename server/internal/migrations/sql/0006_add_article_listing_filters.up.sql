@@ -0,0 +1,26 @@
+-- Read durations are scraped as text ("4m", "1h 5m"), the generated
+-- column makes them filterable and sortable
+CREATE OR REPLACE FUNCTION read_duration_minutes(duration text)
+RETURNS integer
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT CASE
+        WHEN duration IS NULL OR duration !~ '\d' THEN NULL
+        WHEN duration ~ '^\s*\d+\s*$' THEN trim(duration)::integer
+        ELSE coalesce(substring(duration FROM '(\d+)\s*h')::integer, 0) * 60 +
+             coalesce(substring(duration FROM '(\d+)\s*m')::integer, 0)
+    END
+$$;
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS "readDurationMinutes" integer
+        GENERATED ALWAYS AS (read_duration_minutes("readDuration")) STORED,
+    ADD COLUMN IF NOT EXISTS "sourceTag" text DEFAULT NULL;
+
+-- Every article so far was scraped from hackernoon's bitcoin tag page
+UPDATE articles SET "sourceTag" = 'bitcoin' WHERE "sourceTag" IS NULL;
+
+CREATE INDEX IF NOT EXISTS "idx_articles_readDurationMinutes" ON articles ("readDurationMinutes");
+CREATE INDEX IF NOT EXISTS "idx_articles_sourceTag" ON articles ("sourceTag");
+CREATE INDEX IF NOT EXISTS "idx_articles_authorID_postedAt" ON articles ("authorID", "postedAt");
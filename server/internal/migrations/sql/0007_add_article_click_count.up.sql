@@ -0,0 +1,2 @@
+-- Incremented in place by the short link redirect, Save never writes it
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS "clickCount" integer NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS article_tags;
+DROP TABLE IF EXISTS tags;
+
+DROP FUNCTION IF EXISTS tag_slug(text);
@@ -0,0 +1,48 @@
+-- Normalizes tag strings the way models.TagSlug does, e.g "#Web 3"
+-- becomes "web-3"
+CREATE OR REPLACE FUNCTION tag_slug(name text)
+RETURNS text
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT trim(BOTH '-' FROM regexp_replace(lower(coalesce(name, '')), '[^a-z0-9]+', '-', 'g'))
+$$;
+
+CREATE TABLE IF NOT EXISTS tags (
+    "id" uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    "slug" text NOT NULL,
+    "name" text NOT NULL,
+    "aliases" text[] NOT NULL DEFAULT '{}',
+    "createdAt" timestamptz DEFAULT now(),
+    "updatedAt" timestamptz DEFAULT now(),
+    CONSTRAINT "uni_tags_slug" UNIQUE ("slug")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_tags_aliases" ON tags USING GIN ("aliases");
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    "articleID" uuid NOT NULL REFERENCES articles ("id") ON UPDATE CASCADE ON DELETE CASCADE,
+    "tagID" uuid NOT NULL REFERENCES tags ("id") ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY ("articleID", "tagID")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_article_tags_tagID" ON article_tags ("tagID");
+
+-- One tag per slug, named after its most used spelling without the
+-- "#", so "#Bitcoin" and "#bitcoin" merge into one tag
+INSERT INTO tags ("slug", "name")
+SELECT DISTINCT ON (slug) slug, name
+FROM (
+    SELECT tag_slug(tag) AS slug, trim(LEADING '#' FROM trim(tag)) AS name, count(*) AS uses
+    FROM articles
+    WHERE tag_slug(tag) <> ''
+    GROUP BY 1, 2
+) spellings
+ORDER BY slug, uses DESC, name
+ON CONFLICT ("slug") DO NOTHING;
+
+INSERT INTO article_tags ("articleID", "tagID")
+SELECT articles.id, tags.id
+FROM articles
+JOIN tags ON tags.slug = tag_slug(articles.tag)
+ON CONFLICT DO NOTHING;
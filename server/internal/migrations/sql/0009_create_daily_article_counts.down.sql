@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS "count_article_days_redated" ON articles;
+DROP TRIGGER IF EXISTS "count_article_days" ON articles;
+
+DROP FUNCTION IF EXISTS count_article_days();
+DROP FUNCTION IF EXISTS rebuild_daily_article_counts(text);
+
+DROP TABLE IF EXISTS daily_article_counts;
+DROP TABLE IF EXISTS daily_article_count_zones;
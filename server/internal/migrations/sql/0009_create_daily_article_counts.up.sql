@@ -0,0 +1,74 @@
+-- Zones the day counts are kept for, a zone is added the first time
+-- its calendar is requested
+CREATE TABLE IF NOT EXISTS daily_article_count_zones (
+    "timeZone" text PRIMARY KEY,
+    "createdAt" timestamptz DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS daily_article_counts (
+    "timeZone" text NOT NULL REFERENCES daily_article_count_zones ("timeZone") ON DELETE CASCADE,
+    "day" date NOT NULL,
+    "count" integer NOT NULL,
+    PRIMARY KEY ("timeZone", "day")
+);
+
+-- Recounts every day of a zone from the articles table
+CREATE OR REPLACE FUNCTION rebuild_daily_article_counts(zone text)
+RETURNS void
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    DELETE FROM daily_article_counts WHERE "timeZone" = zone;
+
+    INSERT INTO daily_article_counts ("timeZone", "day", "count")
+    SELECT zone, ("postedAt" AT TIME ZONE zone)::date, count(*)
+    FROM articles
+    WHERE "postedAt" IS NOT NULL
+    GROUP BY 2;
+END
+$$;
+
+-- Moves an article between days in every zone, in the transaction of
+-- the statement that created, deleted or re-dated it
+CREATE OR REPLACE FUNCTION count_article_days()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD."postedAt" IS NOT NULL THEN
+        UPDATE daily_article_counts counts
+        SET "count" = counts."count" - 1
+        FROM daily_article_count_zones zones
+        WHERE counts."timeZone" = zones."timeZone"
+          AND counts."day" = (OLD."postedAt" AT TIME ZONE zones."timeZone")::date;
+
+        DELETE FROM daily_article_counts counts
+        USING daily_article_count_zones zones
+        WHERE counts."timeZone" = zones."timeZone"
+          AND counts."day" = (OLD."postedAt" AT TIME ZONE zones."timeZone")::date
+          AND counts."count" <= 0;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW."postedAt" IS NOT NULL THEN
+        INSERT INTO daily_article_counts ("timeZone", "day", "count")
+        SELECT "timeZone", (NEW."postedAt" AT TIME ZONE "timeZone")::date, 1
+        FROM daily_article_count_zones
+        ON CONFLICT ("timeZone", "day") DO UPDATE SET "count" = daily_article_counts."count" + 1;
+    END IF;
+
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER "count_article_days"
+AFTER INSERT OR DELETE ON articles
+FOR EACH ROW EXECUTE FUNCTION count_article_days();
+
+CREATE TRIGGER "count_article_days_redated"
+AFTER UPDATE OF "postedAt" ON articles
+FOR EACH ROW
+WHEN (OLD."postedAt" IS DISTINCT FROM NEW."postedAt")
+EXECUTE FUNCTION count_article_days();
+
+INSERT INTO daily_article_count_zones ("timeZone") VALUES ('UTC') ON CONFLICT DO NOTHING;
+SELECT rebuild_daily_article_counts('UTC');
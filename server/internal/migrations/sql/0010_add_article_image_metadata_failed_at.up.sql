@@ -0,0 +1,3 @@
+-- Set when the image of an article can't be fetched or decoded, the
+-- metadata backfill skips those articles until it is cleared
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS "imageMetadataFailedAt" timestamptz DEFAULT NULL;
@@ -0,0 +1,11 @@
+-- Presigned uploads waiting for CompleteUpload, with the API key that
+-- presigned them. Rows past expiresAt are deleted with their object
+CREATE TABLE IF NOT EXISTS pending_uploads (
+    "key" text PRIMARY KEY,
+    "uploadedBy" text NOT NULL,
+    "contentType" text NOT NULL,
+    "expiresAt" timestamptz NOT NULL,
+    "createdAt" timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS "idx_pending_uploads_expiresAt" ON pending_uploads ("expiresAt");
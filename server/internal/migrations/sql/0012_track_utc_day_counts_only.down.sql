@@ -0,0 +1,2 @@
+-- Dropped zones aren't known anymore, UTC stays tracked
+SELECT 1;
@@ -0,0 +1,4 @@
+-- Day counts are kept for UTC and the zones added with the
+-- rebuild-day-counts command. Zones added by requests are dropped,
+-- their calendars are counted from the articles table instead
+DELETE FROM daily_article_count_zones WHERE "timeZone" <> 'UTC';
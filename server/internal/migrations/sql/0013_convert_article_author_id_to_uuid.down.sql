@@ -0,0 +1,2 @@
+-- The baseline declares authorID as uuid, there is nothing to revert
+SELECT 1;
@@ -0,0 +1,25 @@
+-- authorID is a uuid like the authors id it references. Databases
+-- created from an earlier baseline with a text column are converted,
+-- the search trigger lists the column so it's recreated around it
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND table_name = 'articles'
+          AND column_name = 'authorID'
+          AND data_type <> 'uuid'
+    ) THEN
+        DROP TRIGGER IF EXISTS articles_search_vector_update ON articles;
+        ALTER TABLE articles DROP CONSTRAINT IF EXISTS "fk_authors_article";
+
+        ALTER TABLE articles ALTER COLUMN "authorID" TYPE uuid USING "authorID"::uuid;
+
+        ALTER TABLE articles ADD CONSTRAINT "fk_authors_article"
+            FOREIGN KEY ("authorID") REFERENCES authors ("id")
+            ON UPDATE CASCADE ON DELETE CASCADE;
+        CREATE TRIGGER articles_search_vector_update
+            BEFORE INSERT OR UPDATE OF title, tag, "authorID", body ON articles
+            FOR EACH ROW EXECUTE FUNCTION articles_search_vector_trigger();
+    END IF;
+END $$;
@@ -0,0 +1,19 @@
+-- Records the spellings 0008 merged into one tag as its aliases, e.g
+-- "bitcoin" on the tag named "Bitcoin". FindOrCreate keeps adding the
+-- new ones
+UPDATE tags
+SET aliases = ARRAY(
+        SELECT DISTINCT spelling
+        FROM unnest(tags.aliases || spellings.names) spelling
+        WHERE spelling <> tags.name
+        ORDER BY spelling
+    ),
+    "updatedAt" = now()
+FROM (
+    SELECT tag_slug(tag) AS slug, array_agg(DISTINCT trim(LEADING '#' FROM trim(tag))) AS names
+    FROM articles
+    WHERE tag_slug(tag) <> ''
+    GROUP BY 1
+) spellings
+WHERE tags.slug = spellings.slug
+  AND EXISTS (SELECT 1 FROM unnest(spellings.names) spelling WHERE spelling <> tags.name);
@@ -59,11 +59,8 @@ func Db() *gorm.DB {
 
 		log.Println("Connected to postgres successfully")
 
-		err = gormDB.AutoMigrate(&Article{}, &Author{})
-		if err != nil {
-			log.Fatal("Failed to make auto migration", err)
-		}
-		log.Println("Auto Migration successful")
+		// The schema is managed by versioned migrations, see the
+		// internal/migrations package and the "migrate" command
 	})
 
 	return gormDB
@@ -0,0 +1,11 @@
+package models
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by every repository backend.
+// Check them with errors.Is, the messages read as
+// "<entity> not found" once wrapped
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("already exists")
+)
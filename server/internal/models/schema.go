@@ -2,24 +2,36 @@ package models
 
 import (
 	"time"
-)
 
-var db = Db()
+	"github.com/lib/pq"
+)
 
 type Article struct {
-	ID            string    `gorm:"column:id;type:uuid;primaryKey" json:"id"`
-	AuthorID      string    `gorm:"column:authorID;not null;index" json:"authorID"`
-	Tag           string    `gorm:"column:tag;not null;index" json:"tag"`
-	TagIndex      string    `gorm:"column:tagIndex;index" json:"tagIndex"`
-	Title         string    `gorm:"column:title;not null;index" json:"title"`
-	Href          string    `gorm:"column:href;default:null" json:"href"`
-	ImageUrl      string    `gorm:"column:imageUrl;not null" json:"imageUrl"`
-	ImageFilename string    `gorm:"column:imageFilename;default:null" json:"imageFilename"`
-	PostedAt      time.Time `gorm:"column:postedAt;index" json:"postedAt"`
-	ReadDuration  string    `gorm:"column:readDuration" json:"readDuration"`
-	CreatedAt     time.Time `gorm:"column:createdAt;index" json:"createdAt"`
-	UpdatedAt     time.Time `gorm:"column:updatedAt;index" json:"updatedAt"`
-	Author        *Author   `gorm:"foreignKey:AuthorID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"author,omitempty"`
+	ID                    string     `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	AuthorID              string     `gorm:"column:authorID;type:uuid;not null;index" json:"authorID"`
+	Tag                   string     `gorm:"column:tag;not null;index" json:"tag"`
+	TagIndex              string     `gorm:"column:tagIndex;index" json:"tagIndex"`
+	Title                 string     `gorm:"column:title;not null;index" json:"title"`
+	Href                  string     `gorm:"column:href;default:null" json:"href"`
+	ImageUrl              string     `gorm:"column:imageUrl;not null" json:"imageUrl"`
+	ImageFilename         string     `gorm:"column:imageFilename;default:null" json:"imageFilename"`
+	ImageWidth            int        `gorm:"column:imageWidth;default:null" json:"imageWidth"`
+	ImageHeight           int        `gorm:"column:imageHeight;default:null" json:"imageHeight"`
+	ImageAspectRatio      float64    `gorm:"column:imageAspectRatio;default:null" json:"imageAspectRatio"`
+	ImageDominantColor    string     `gorm:"column:imageDominantColor;default:null" json:"imageDominantColor"`
+	ImageBlurHash         string     `gorm:"column:imageBlurHash;default:null" json:"imageBlurHash"`
+	ImageMetadataFailedAt *time.Time `gorm:"column:imageMetadataFailedAt;default:null" json:"imageMetadataFailedAt,omitempty"`
+	PostedAt              time.Time  `gorm:"column:postedAt;index" json:"postedAt"`
+	ReadDuration          string     `gorm:"column:readDuration" json:"readDuration"`
+	ReadDurationMinutes   int        `gorm:"column:readDurationMinutes;->;-:migration" json:"readDurationMinutes"`
+	SourceTag             string     `gorm:"column:sourceTag;index;default:null" json:"sourceTag"`
+	ClickCount            int        `gorm:"column:clickCount;->;-:migration" json:"clickCount"`
+	Body                  string     `gorm:"column:body;default:null" json:"body,omitempty"`
+	SearchRank            float64    `gorm:"column:searchRank;->;-:migration" json:"searchRank,omitempty"`
+	SearchHighlight       string     `gorm:"column:searchHighlight;->;-:migration" json:"searchHighlight,omitempty"`
+	CreatedAt             time.Time  `gorm:"column:createdAt;index" json:"createdAt"`
+	UpdatedAt             time.Time  `gorm:"column:updatedAt;index" json:"updatedAt"`
+	Author                *Author    `gorm:"foreignKey:AuthorID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"author,omitempty"`
 }
 
 type Author struct {
@@ -30,5 +42,48 @@ type Author struct {
 	PageUrl        string     `gorm:"column:pageUrl;default:null" json:"pageUrl"`
 	CreatedAt      time.Time  `gorm:"column:createdAt;index" json:"createdAt"`
 	UpdatedAt      time.Time  `gorm:"column:updatedAt;index" json:"updatedAt"`
+	ArticleCount   int64      `gorm:"column:articleCount;->;-:migration" json:"articleCount,omitempty"`
+	LatestPostedAt *time.Time `gorm:"column:latestPostedAt;->;-:migration" json:"latestPostedAt,omitempty"`
 	Article        []*Article `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"articles,omitempty"`
 }
+
+type Tag struct {
+	ID           string         `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	Slug         string         `gorm:"column:slug;unique;not null" json:"slug"`
+	Name         string         `gorm:"column:name;not null" json:"name"`
+	Aliases      pq.StringArray `gorm:"column:aliases;type:text[];not null;default:'{}'" json:"aliases"`
+	CreatedAt    time.Time      `gorm:"column:createdAt" json:"createdAt"`
+	UpdatedAt    time.Time      `gorm:"column:updatedAt" json:"updatedAt"`
+	ArticleCount int64          `gorm:"column:articleCount;->;-:migration" json:"articleCount,omitempty"`
+}
+
+// ArticleTag links an article to one of its tags
+type ArticleTag struct {
+	ArticleID string `gorm:"column:articleID;type:uuid;primaryKey" json:"articleID"`
+	TagID     string `gorm:"column:tagID;type:uuid;primaryKey;index" json:"tagID"`
+}
+
+type FileRecord struct {
+	ID            string    `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	URL           string    `gorm:"column:url;not null" json:"url"`
+	Filename      string    `gorm:"column:filename;unique;not null" json:"filename"`
+	OriginalName  string    `gorm:"column:originalName" json:"originalName"`
+	Size          int64     `gorm:"column:size" json:"size"`
+	ContentType   string    `gorm:"column:contentType" json:"contentType"`
+	Width         int       `gorm:"column:width;default:null" json:"width"`
+	Height        int       `gorm:"column:height;default:null" json:"height"`
+	DominantColor string    `gorm:"column:dominantColor;default:null" json:"dominantColor"`
+	BlurHash      string    `gorm:"column:blurHash;default:null" json:"blurHash"`
+	UploadedBy    string    `gorm:"column:uploadedBy;index" json:"uploadedBy"`
+	CreatedAt     time.Time `gorm:"column:createdAt;index" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"column:updatedAt;index" json:"updatedAt"`
+}
+
+// PendingUpload is a presigned upload that hasn't been completed yet
+type PendingUpload struct {
+	Key         string    `gorm:"column:key;primaryKey" json:"key"`
+	UploadedBy  string    `gorm:"column:uploadedBy;not null" json:"uploadedBy"`
+	ContentType string    `gorm:"column:contentType;not null" json:"contentType"`
+	ExpiresAt   time.Time `gorm:"column:expiresAt;not null;index" json:"expiresAt"`
+	CreatedAt   time.Time `gorm:"column:createdAt" json:"createdAt"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	uuid := uuid.New().String()
+	tx.Statement.SetColumn("ID", uuid)
+	return nil
+}
+
+// TagSlug normalizes a tag the way the tag_slug() SQL function does:
+// lower case, with every run of characters other than a-z and 0-9
+// turned into one "-", e.g "#Web 3" becomes "web-3"
+func TagSlug(name string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return slug.String()
+}
+
+// TagName is the display name of a scraped tag, without its "#"
+func TagName(tag string) string {
+	return strings.TrimLeft(strings.TrimSpace(tag), "#")
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParseArticleFilter reads the filter query parameters shared by the
+// article listings: authorID, author (name), tag, sourceTag, from, to,
+// minRead, maxRead (minutes), hasImage and linkStatus (linked|missing)
+func ParseArticleFilter(c *fiber.Ctx) (repository.ArticleFilter, error) {
+	filter := repository.ArticleFilter{
+		AuthorID:   c.Query("authorID"),
+		AuthorName: strings.TrimSpace(c.Query("author")),
+		Tag:        c.Query("tag"),
+		SourceTag:  c.Query("sourceTag"),
+	}
+
+	var err error
+	if filter.PostedFrom, err = parseFilterDate(c.Query("from"), false); err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid from! Must be a date or an ISO 8601 string.")
+	}
+	if filter.PostedTo, err = parseFilterDate(c.Query("to"), true); err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid to! Must be a date or an ISO 8601 string.")
+	}
+	if !filter.PostedFrom.IsZero() && !filter.PostedTo.IsZero() && filter.PostedTo.Before(filter.PostedFrom) {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid range! to must not be before from.")
+	}
+
+	if filter.MinReadMinutes, err = parseMinutes(c.Query("minRead")); err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid minRead! Must be a positive number of minutes.")
+	}
+	if filter.MaxReadMinutes, err = parseMinutes(c.Query("maxRead")); err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid maxRead! Must be a positive number of minutes.")
+	}
+
+	if hasImageParam := c.Query("hasImage"); hasImageParam != "" {
+		hasImage, err := strconv.ParseBool(hasImageParam)
+		if err != nil {
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid hasImage! Must be true or false.")
+		}
+		filter.HasImage = &hasImage
+	}
+
+	switch c.Query("linkStatus") {
+	case "":
+	case "linked":
+		hasLink := true
+		filter.HasLink = &hasLink
+	case "missing":
+		hasLink := false
+		filter.HasLink = &hasLink
+	default:
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid linkStatus! Must be linked or missing.")
+	}
+
+	return filter, nil
+}
+
+// ParseArticleSort reads the sort query parameter, newest by default
+func ParseArticleSort(c *fiber.Ctx) (repository.SortOrder, error) {
+	sortParam := c.Query("sort")
+	if sortParam == "" {
+		return repository.SortNewest, nil
+	}
+
+	sort, ok := repository.ArticleSorts[sortParam]
+	if !ok {
+		return sort, fiber.NewError(fiber.StatusBadRequest, "Invalid sort! Must be one of newest, oldest, longest or title.")
+	}
+	return sort, nil
+}
+
+// parseFilterDate accepts an ISO 8601 timestamp or a plain date. A
+// plain date ending a range covers the whole day
+func parseFilterDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		if endOfDay {
+			return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func parseMinutes(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return minutes, nil
+}
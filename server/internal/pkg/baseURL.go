@@ -0,0 +1,24 @@
+package pkg
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HostDependentLocal is set on requests whose response links to the
+// Host they were sent to, the response cache doesn't store those
+const HostDependentLocal = "hostDependent"
+
+// PublicBaseURL returns PUBLIC_BASE_URL for the absolute links of a
+// response e.g feeds and pagination. Without it the request's own base
+// url is used, which the client controls, so the response is marked
+// host dependent
+func PublicBaseURL(c *fiber.Ctx) string {
+	if baseURL := os.Getenv("PUBLIC_BASE_URL"); baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+	c.Locals(HostDependentLocal, true)
+	return c.BaseURL()
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NotModified sets the ETag and Last-Modified validators of the
+// response and reports whether the client's copy is still current.
+// If-None-Match wins over If-Modified-Since as RFC 9110 asks, etags
+// are compared weakly. Fiber's Ctx.Fresh answers true for any
+// If-Modified-Since without If-None-Match, hence this helper
+func NotModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		c.Set(fiber.HeaderETag, etag)
+	}
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(noneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if modifiedSince := c.Get(fiber.HeaderIfModifiedSince); modifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(modifiedSince)
+		if err != nil {
+			return false
+		}
+		// Last-Modified has a precision of one second
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
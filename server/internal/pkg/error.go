@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -24,6 +25,20 @@ var DefaultErrorHandler = func(c *fiber.Ctx, err error) error {
 		}
 	}
 
+	// Repository errors reach here unwrapped so handlers don't have
+	// to translate them one by one. The message comes from the sentinel
+	// alone, whatever else is in the chain stays in the logged detail
+	if errors.Is(err, models.ErrNotFound) {
+		code = fiber.StatusNotFound
+		message = "Resource " + models.ErrNotFound.Error() + "!"
+		status = "fail"
+	}
+	if errors.Is(err, models.ErrConflict) {
+		code = fiber.StatusConflict
+		message = "Resource " + models.ErrConflict.Error() + "!"
+		status = "fail"
+	}
+
 	errDetailMsg := struct {
 		Code    int    `json:"code"`
 		Status  string `json:"status"`
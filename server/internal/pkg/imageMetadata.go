@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+
+	"github.com/buckket/go-blurhash"
+	_ "golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// Number of BlurHash components along each axis, 4x3 suits
+// the landscape cover images HackerNoon serves
+const (
+	blurHashXComponents = 4
+	blurHashYComponents = 3
+	thumbnailMaxSize    = 64
+)
+
+type ImageMetadata struct {
+	Width         int     `json:"width"`
+	Height        int     `json:"height"`
+	AspectRatio   float64 `json:"aspectRatio"`
+	DominantColor string  `json:"dominantColor"`
+	BlurHash      string  `json:"blurHash"`
+}
+
+// GetImageMetadata decodes the image and returns its dimensions,
+// dominant colour and a BlurHash placeholder string
+func (ip *ImageProcessor) GetImageMetadata(data []byte) (ImageMetadata, error) {
+	var metadata ImageMetadata
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return metadata, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return metadata, fmt.Errorf("image has no pixels")
+	}
+
+	metadata.Width = bounds.Dx()
+	metadata.Height = bounds.Dy()
+	metadata.AspectRatio = math.Round(float64(bounds.Dx())/float64(bounds.Dy())*10000) / 10000
+
+	// Work on a small thumbnail, both the dominant colour and
+	// the BlurHash only need a rough picture of the image
+	thumbnail := thumbnailOf(img)
+
+	metadata.DominantColor = dominantColor(thumbnail)
+
+	hash, err := blurhash.Encode(blurHashXComponents, blurHashYComponents, thumbnail)
+	if err != nil {
+		return metadata, fmt.Errorf("failed to encode blurhash: %w", err)
+	}
+	metadata.BlurHash = hash
+
+	return metadata, nil
+}
+
+func thumbnailOf(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > thumbnailMaxSize || height > thumbnailMaxSize {
+		if width >= height {
+			height = int(math.Max(1, float64(height*thumbnailMaxSize/width)))
+			width = thumbnailMaxSize
+		} else {
+			width = int(math.Max(1, float64(width*thumbnailMaxSize/height)))
+			height = thumbnailMaxSize
+		}
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Src, nil)
+
+	return thumbnail
+}
+
+// dominantColor buckets pixels into a coarse 4-bit per channel
+// palette and returns the average colour of the fullest bucket
+// as a hex string e.g. #1a2b3c
+func dominantColor(img *image.RGBA) string {
+	type bucket struct {
+		r, g, b, count int
+	}
+	buckets := make(map[int]*bucket)
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			if c.A < 128 {
+				continue
+			}
+			key := int(c.R>>4)<<8 | int(c.G>>4)<<4 | int(c.B>>4)
+
+			b, ok := buckets[key]
+			if !ok {
+				b = &bucket{}
+				buckets[key] = b
+			}
+			b.r += int(c.R)
+			b.g += int(c.G)
+			b.b += int(c.B)
+			b.count++
+		}
+	}
+
+	var top *bucket
+	topKey := -1
+	for key, b := range buckets {
+		if top == nil || b.count > top.count || (b.count == top.count && key < topKey) {
+			top = b
+			topKey = key
+		}
+	}
+	if top == nil {
+		return "#000000"
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", top.r/top.count, top.g/top.count, top.b/top.count)
+}
@@ -89,11 +89,13 @@ package pkg
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
+	"image"
 	"io"
 	"mime/multipart"
-	"net/http"
+	"strings"
 	"time"
 )
 
@@ -122,34 +124,35 @@ func (ip *ImageProcessor) ConvertBase64ToBytes(base64String string) ([]byte, err
 	return imageBytes, nil
 }
 
+var imageFetcher = NewRemoteFetcher()
+
 // FetchImageFromURL downloads an image from the given URL
-// and returns the image data as bytes
+// and returns the image data as bytes. The request goes through
+// the SSRF-safe RemoteFetcher and the body must be a JPEG, PNG, GIF
+// or WebP both by its Content-Type header and by decoding its header,
+// so documents such as SVG are never accepted as images
 func (ip *ImageProcessor) GetImageFromURL(url string) ([]byte, error) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	resp, err := client.Get(url)
+	remoteFile, err := imageFetcher.Fetch(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch image from URL: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch image: HTTP %d", resp.StatusCode)
-	}
 
-	contentType := resp.Header.Get("Content-Type")
-	if contentType == "" || !isImageContentType(contentType) {
-		return nil, fmt.Errorf("URL does not point to an image, content-type: %s", contentType)
+	if remoteFile.ContentType == "" || !isImageContentType(remoteFile.ContentType) {
+		return nil, fmt.Errorf("URL does not point to an image, content-type: %s", remoteFile.ContentType)
 	}
 
-	imgData, err := io.ReadAll(resp.Body)
+	_, format, err := image.DecodeConfig(bytes.NewReader(remoteFile.Data))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read image data: %w", err)
+		return nil, fmt.Errorf("URL content is not a valid image: %w", err)
+	}
+	if !rasterImageFormats[format] {
+		return nil, fmt.Errorf("URL content is not a supported image format: %s", format)
 	}
 
-	return imgData, nil
+	return remoteFile.Data, nil
 }
 
 // GetContentTypeFromBinary detects the content type of an image from its binary data
@@ -188,6 +191,15 @@ func (ip *ImageProcessor) BinaryToReader(data []byte) io.Reader {
 	return bytes.NewReader(data)
 }
 
+// rasterImageFormats are the image.DecodeConfig formats remote images
+// may have. BMP and TIFF decode too but aren't fetched
+var rasterImageFormats = map[string]bool{
+	"jpeg": true,
+	"png":  true,
+	"gif":  true,
+	"webp": true,
+}
+
 func isImageContentType(contentType string) bool {
 	imageTypes := []string{
 		"image/jpeg",
@@ -195,11 +207,9 @@ func isImageContentType(contentType string) bool {
 		"image/png",
 		"image/gif",
 		"image/webp",
-		"image/bmp",
-		"image/tiff",
-		"image/svg+xml",
 	}
 
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
 	for _, imgType := range imageTypes {
 		if contentType == imgType {
 			return true
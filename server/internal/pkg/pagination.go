@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"net/url"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParsePageRequest reads the limit and cursor query parameters of a
+// keyset paginated route
+func ParsePageRequest[T any](c *fiber.Ctx, sort repository.Sort[T]) (repository.PageRequest, error) {
+	limit, err := ValidateQueryLimit(c.Query("limit"))
+	if err != nil {
+		return repository.PageRequest{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	page := repository.PageRequest{Limit: int(limit)}
+
+	if cursorParam := c.Query("cursor"); cursorParam != "" {
+		cursor, err := repository.DecodeCursor(cursorParam, sort)
+		if err != nil {
+			return page, fiber.NewError(fiber.StatusBadRequest, "Invalid cursor! "+err.Error())
+		}
+		page.Cursor = cursor
+	}
+
+	return page, nil
+}
+
+// PageLink returns the request URL with its cursor parameter set to
+// cursor, or an empty string when there is no cursor
+func PageLink(c *fiber.Ctx, cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		query = url.Values{}
+	}
+	query.Set("cursor", cursor)
+
+	return PublicBaseURL(c) + c.Path() + "?" + query.Encode()
+}
+
+// CursorPagination builds the pagination envelope of a keyset page
+func CursorPagination(c *fiber.Ctx, request repository.PageRequest, page repository.PageInfo) map[string]interface{} {
+	return map[string]interface{}{
+		"limit":      request.Limit,
+		"count":      page.Total,
+		"nextCursor": page.NextCursor,
+		"prevCursor": page.PrevCursor,
+		"next":       PageLink(c, page.NextCursor),
+		"prev":       PageLink(c, page.PrevCursor),
+	}
+}
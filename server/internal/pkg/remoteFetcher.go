@@ -0,0 +1,191 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+)
+
+var ErrBlockedAddress = errors.New("destination address is not allowed")
+var ErrResponseTooLarge = errors.New("response body exceeds the maximum allowed size")
+
+// RemoteFetcher downloads remote resources without trusting the URL.
+// It only dials public addresses (checked after DNS resolution so
+// rebinding can't sneak a private address in), caps redirects and
+// refuses to read more than MaxBytes of the body
+type RemoteFetcher struct {
+	MaxBytes        int64
+	MaxRedirects    int
+	Timeout         time.Duration
+	AllowPrivateIPs bool
+	client          *http.Client
+	once            sync.Once
+}
+
+type RemoteFile struct {
+	Data        []byte
+	ContentType string
+	FinalURL    string
+}
+
+// Ranges that are not covered by the net/netip helpers
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+func NewRemoteFetcher() *RemoteFetcher {
+	return &RemoteFetcher{
+		MaxBytes:     constants.REMOTE_FETCH_MAX_BYTES,
+		MaxRedirects: constants.REMOTE_FETCH_MAX_REDIRECTS,
+		Timeout:      30 * time.Second,
+	}
+}
+
+// IsPublicAddress reports whether the ip is routable on the public
+// internet i.e not loopback, private, link-local or reserved
+func IsPublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (rf *RemoteFetcher) httpClient() *http.Client {
+	rf.once.Do(rf.buildClient)
+	return rf.client
+}
+
+func (rf *RemoteFetcher) buildClient() {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if rf.AllowPrivateIPs {
+				return nil
+			}
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+			}
+			if !IsPublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Second,
+	}
+
+	rf.client = &http.Client{
+		Timeout:   rf.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > rf.MaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", rf.MaxRedirects)
+			}
+			return validateFetchURL(req.URL)
+		},
+	}
+}
+
+func validateFetchURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme: %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("URL has no host")
+	}
+	if u.User != nil {
+		return fmt.Errorf("URL must not contain credentials")
+	}
+	return nil
+}
+
+// Fetch downloads rawURL and returns the body along with the
+// media type parsed from the Content-Type header (parameters stripped)
+func (rf *RemoteFetcher) Fetch(ctx context.Context, rawURL string) (RemoteFile, error) {
+	var remoteFile RemoteFile
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return remoteFile, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := validateFetchURL(parsedURL); err != nil {
+		return remoteFile, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedURL.String(), nil)
+	if err != nil {
+		return remoteFile, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := rf.httpClient().Do(req)
+	if err != nil {
+		return remoteFile, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return remoteFile, fmt.Errorf("failed to fetch URL: HTTP %d", resp.StatusCode)
+	}
+
+	if resp.ContentLength > rf.MaxBytes {
+		return remoteFile, fmt.Errorf("%w: %d bytes", ErrResponseTooLarge, resp.ContentLength)
+	}
+
+	if header := resp.Header.Get("Content-Type"); header != "" {
+		mediaType, _, err := mime.ParseMediaType(header)
+		if err != nil {
+			return remoteFile, fmt.Errorf("invalid content-type %q: %w", header, err)
+		}
+		remoteFile.ContentType = mediaType
+	}
+
+	// Read one byte past the limit so an oversized body is detected
+	// even when the server lies about or omits Content-Length
+	data, err := io.ReadAll(io.LimitReader(resp.Body, rf.MaxBytes+1))
+	if err != nil {
+		return remoteFile, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(data)) > rf.MaxBytes {
+		return remoteFile, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, rf.MaxBytes)
+	}
+
+	remoteFile.Data = data
+	remoteFile.FinalURL = resp.Request.URL.String()
+
+	return remoteFile, nil
+}
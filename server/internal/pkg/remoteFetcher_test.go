@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		// CGNAT
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+		// IPv4-mapped addresses are checked as the IPv4 they carry
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"::ffff:8.8.8.8", true},
+		// NAT64 reaches IPv4 addresses through the local gateway
+		{"64:ff9b::7f00:1", false},
+		{"64:ff9b::808:808", false},
+		{"198.18.0.1", false},
+		{"240.0.0.1", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ip, func(t *testing.T) {
+			if got := IsPublicAddress(netip.MustParseAddr(test.ip)); got != test.want {
+				t.Errorf("IsPublicAddress(%s) = %v, want %v", test.ip, got, test.want)
+			}
+		})
+	}
+}
+
+// newTestFetcher returns a fetcher allowed to reach httptest servers,
+// which listen on loopback
+func newTestFetcher(maxBytes int64, maxRedirects int) *RemoteFetcher {
+	fetcher := NewRemoteFetcher()
+	fetcher.MaxBytes = maxBytes
+	fetcher.MaxRedirects = maxRedirects
+	fetcher.AllowPrivateIPs = true
+	return fetcher
+}
+
+func TestFetchBlocksPrivateTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("the fetcher reached %s", r.URL)
+	}))
+	defer server.Close()
+
+	_, err := NewRemoteFetcher().Fetch(context.Background(), server.URL)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("Fetch(%s) err = %v, want ErrBlockedAddress", server.URL, err)
+	}
+}
+
+func TestFetchRedirects(t *testing.T) {
+	// /hop/n redirects n more times before answering
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if hops > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/hop/%d", hops-1), http.StatusFound)
+			return
+		}
+		w.Write([]byte("done"))
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(1024, 2)
+	file, err := fetcher.Fetch(context.Background(), server.URL+"/hop/2")
+	if err != nil {
+		t.Fatalf("Fetch with 2 redirects: %v", err)
+	}
+	if string(file.Data) != "done" || !strings.HasSuffix(file.FinalURL, "/hop/0") {
+		t.Fatalf("Fetch with 2 redirects = %q from %s, want done from /hop/0", file.Data, file.FinalURL)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/hop/3"); err == nil ||
+		!strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Fatalf("Fetch with 3 redirects err = %v, want it stopped after 2", err)
+	}
+}
+
+func TestFetchRefusesOversizedBodies(t *testing.T) {
+	body := strings.Repeat("a", 64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/with-length" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		} else {
+			// Flushing first makes the body chunked, without a length
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/with-length", "/without-length"} {
+		t.Run(path, func(t *testing.T) {
+			_, err := newTestFetcher(32, 0).Fetch(context.Background(), server.URL+path)
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Fatalf("Fetch(%s) err = %v, want ErrResponseTooLarge", path, err)
+			}
+
+			file, err := newTestFetcher(64, 0).Fetch(context.Background(), server.URL+path)
+			if err != nil || string(file.Data) != body {
+				t.Fatalf("Fetch(%s) at the limit = %q, %v, want the body", path, file.Data, err)
+			}
+		})
+	}
+}
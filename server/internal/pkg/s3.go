@@ -2,6 +2,7 @@ package pkg
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -24,17 +25,6 @@ type S3Client struct {
 	region     string
 }
 
-// Database model for storing file references
-type FileRecord struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	URL          string    `gorm:"not null" json:"url"`
-	Filename     string    `gorm:"not null" json:"filename"`
-	OriginalName string    `json:"original_name"`
-	Size         int64     `json:"size"`
-	ContentType  string    `json:"content_type"`
-	CreatedAt    time.Time `json:"created_at"`
-}
-
 type UploadResponse struct {
 	ID          uint   `json:"id"`
 	URL         string `json:"url"`
@@ -70,7 +60,9 @@ func (s3c *S3Client) NewS3Client(ctx context.Context) (*S3Client, error) {
 	}, nil
 }
 
-func (s3c *S3Client) UploadFile(ctx context.Context, file io.Reader, originalFilename string, contentType string, fileSize int64) (*UploadResponse, error) {
+// NewObjectKey builds a unique object key that keeps the
+// extension of the original filename
+func (s3c *S3Client) NewObjectKey(prefix, originalFilename string) string {
 	ext := filepath.Ext(originalFilename)
 
 	if queryIndex := strings.Index(ext, "?"); queryIndex != -1 {
@@ -78,7 +70,18 @@ func (s3c *S3Client) UploadFile(ctx context.Context, file io.Reader, originalFil
 	}
 
 	uniqueID := uuid.New().String()
-	filename := fmt.Sprintf("%s%s", uniqueID, ext)
+	return fmt.Sprintf("%s%s%s", prefix, uniqueID, ext)
+}
+
+// ObjectURL returns the permanent URL of the object
+func (s3c *S3Client) ObjectURL(filename string) string {
+	// Format: https://bucket-name.s3.region.amazonaws.com/filename
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s",
+		s3c.bucketName, s3c.region, filename)
+}
+
+func (s3c *S3Client) UploadFile(ctx context.Context, file io.Reader, originalFilename string, contentType string, fileSize int64) (*UploadResponse, error) {
+	filename := s3c.NewObjectKey("", originalFilename)
 
 	if contentType == "" {
 		contentType = "application/octet-stream"
@@ -97,18 +100,31 @@ func (s3c *S3Client) UploadFile(ctx context.Context, file io.Reader, originalFil
 		return nil, fmt.Errorf("failed to upload file to S3: %v", err)
 	}
 
-	// Format: https://bucket-name.s3.region.amazonaws.com/filename
-	permanentURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s",
-		s3c.bucketName, s3c.region, filename)
-
 	return &UploadResponse{
-		URL:         permanentURL,
+		URL:         s3c.ObjectURL(filename),
 		Filename:    filename,
 		Size:        fileSize,
 		ContentType: contentType,
 	}, nil
 }
 
+// PutFile uploads the file under the given key, replacing the
+// object stored there
+func (s3c *S3Client) PutFile(ctx context.Context, file io.Reader, filename string, contentType string) error {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s3c.bucketName),
+		Key:         aws.String(filename),
+		Body:        file,
+		ContentType: aws.String(contentType),
+	}
+
+	_, err := s3c.client.PutObject(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to upload file to S3: %v", err)
+	}
+	return nil
+}
+
 func (s3c *S3Client) DeleteFile(ctx context.Context, filename string) error {
 	input := &s3.DeleteObjectInput{
 		Bucket: aws.String(s3c.bucketName),
@@ -130,8 +146,8 @@ func (s3c *S3Client) FileExists(ctx context.Context, filename string) (bool, err
 
 	_, err := s3c.client.HeadObject(ctx, input)
 	if err != nil {
-		// var notFound *types.NotFound
-		if err := err.(*types.NotFound); err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
 			return false, nil
 		}
 		return false, err
@@ -139,6 +155,59 @@ func (s3c *S3Client) FileExists(ctx context.Context, filename string) (bool, err
 	return true, nil
 }
 
+// DownloadFile reads the object into memory, refusing objects
+// larger than maxBytes
+func (s3c *S3Client) DownloadFile(ctx context.Context, filename string, maxBytes int64) ([]byte, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s3c.bucketName),
+		Key:    aws.String(filename),
+	}
+
+	output, err := s3c.client.GetObject(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file from S3: %v", err)
+	}
+	defer output.Body.Close()
+
+	if output.ContentLength != nil && *output.ContentLength > maxBytes {
+		return nil, fmt.Errorf("file exceeds the maximum size of %d bytes", maxBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(output.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from S3: %v", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("file exceeds the maximum size of %d bytes", maxBytes)
+	}
+
+	return data, nil
+}
+
+// Generate presigned URL the client can PUT the file to directly.
+// The signature covers the content type and length so the client
+// can't upload something other than what it asked for
+func (s3c *S3Client) GetPresignedUploadURL(ctx context.Context, filename string,
+	contentType string, size int64, duration time.Duration) (string, error) {
+	presignClient := s3.NewPresignClient(s3c.client)
+
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(s3c.bucketName),
+		Key:           aws.String(filename),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}
+
+	presignedURL, err := presignClient.PresignPutObject(ctx, input, func(opts *s3.PresignOptions) {
+		opts.Expires = duration
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned upload URL: %v", err)
+	}
+
+	return presignedURL.URL, nil
+}
+
 // Generate presigned URL for temporary access (optional feature)
 func (s3c *S3Client) GetPresignedURL(ctx context.Context, filename string, duration time.Duration) (string, error) {
 	presignClient := s3.NewPresignClient(s3c.client)
@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// StripMetadata removes EXIF, XMP and text metadata from JPEG, PNG
+// and WebP images without re-encoding the pixel data. Other image
+// types are returned unchanged
+func (ip *ImageProcessor) StripMetadata(data []byte, contentType string) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEGMetadata(data)
+	case "image/png":
+		return stripPNGMetadata(data)
+	case "image/webp":
+		return stripWebPMetadata(data)
+	default:
+		return data, nil
+	}
+}
+
+// stripJPEGMetadata drops APP1-APP15 and COM segments. APP0 (JFIF)
+// is kept since some decoders rely on it
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, fmt.Errorf("invalid JPEG header")
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	out.Write(data[:2])
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("invalid JPEG marker at offset %d", pos)
+		}
+		marker := data[pos+1]
+
+		// Fill bytes may pad the space between segments
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+
+		// Start of scan, everything that follows is entropy coded data
+		if marker == 0xDA {
+			out.Write(data[pos:])
+			return out.Bytes(), nil
+		}
+
+		segmentLength := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + segmentLength
+		if segmentLength < 2 || end > len(data) {
+			return nil, fmt.Errorf("invalid JPEG segment length at offset %d", pos)
+		}
+
+		isMetadata := (marker >= 0xE1 && marker <= 0xEF) || marker == 0xFE
+		if !isMetadata {
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+
+	return nil, fmt.Errorf("JPEG has no image data")
+}
+
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"iTXt": true,
+	"zTXt": true,
+	"tIME": true,
+}
+
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	const signatureLength = 8
+	if len(data) < signatureLength {
+		return nil, fmt.Errorf("invalid PNG header")
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	out.Write(data[:signatureLength])
+
+	pos := signatureLength
+	for pos+8 <= len(data) {
+		chunkLength := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		chunkType := string(data[pos+4 : pos+8])
+		// length + type + data + crc
+		end := pos + 12 + chunkLength
+		if chunkLength < 0 || end > len(data) {
+			return nil, fmt.Errorf("invalid PNG chunk length at offset %d", pos)
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out.Write(data[pos:end])
+		}
+		pos = end
+
+		if chunkType == "IEND" {
+			return out.Bytes(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("PNG has no IEND chunk")
+}
+
+// stripWebPMetadata drops the EXIF and XMP chunks of an extended
+// (VP8X) WebP file and clears their flags in the VP8X header
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	const headerLength = 12
+	if len(data) < headerLength || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("invalid WebP header")
+	}
+
+	var body bytes.Buffer
+	body.Grow(len(data))
+
+	pos := headerLength
+	for pos+8 <= len(data) {
+		chunkType := string(data[pos : pos+4])
+		chunkLength := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		// Chunks are padded to an even size
+		end := pos + 8 + chunkLength + chunkLength%2
+		if end > len(data) {
+			return nil, fmt.Errorf("invalid WebP chunk length at offset %d", pos)
+		}
+
+		switch chunkType {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte{}, data[pos:end]...)
+			if len(chunk) > 8 {
+				// bit 3 is EXIF and bit 2 is XMP
+				chunk[8] &^= 0x08 | 0x04
+			}
+			body.Write(chunk)
+		default:
+			body.Write(data[pos:end])
+		}
+		pos = end
+	}
+
+	out := make([]byte, 0, headerLength+body.Len())
+	out = append(out, "RIFF"...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(4+body.Len()))
+	out = append(out, "WEBP"...)
+	out = append(out, body.Bytes()...)
+
+	return out, nil
+}
@@ -0,0 +1,26 @@
+package pkg
+
+import (
+	"time"
+	// Embeds the IANA database, containers may not ship one
+	_ "time/tzdata"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParseTimezone reads the tz query parameter, an IANA zone name such
+// as Africa/Nairobi, defaulting to UTC
+func ParseTimezone(c *fiber.Ctx) (*time.Location, error) {
+	tzParam := c.Query("tz")
+	if tzParam == "" {
+		return time.UTC, nil
+	}
+
+	// "Local" would mean the server's zone, which the database can't
+	// resolve
+	location, err := time.LoadLocation(tzParam)
+	if err != nil || tzParam == "Local" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid tz! Must be an IANA time zone e.g Africa/Nairobi.")
+	}
+	return location, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+)
+
+// AuthorSortOrder orders author listings. Listed authors carry their
+// ArticleCount and LatestPostedAt
+type AuthorSortOrder = Sort[models.Author]
+
+var SortMostArticles = AuthorSortOrder{
+	Name: "articles",
+	Desc: true,
+	Key: func(author models.Author) string {
+		return strconv.FormatInt(author.ArticleCount, 10)
+	},
+	ParseKey: parseIntKey,
+}
+
+// SortLatestPost orders authors by their newest article, authors
+// without articles sort as if they last posted at the Unix epoch
+var SortLatestPost = AuthorSortOrder{
+	Name: "latest",
+	Desc: true,
+	Key: func(author models.Author) string {
+		latest := time.Unix(0, 0)
+		if author.LatestPostedAt != nil {
+			latest = *author.LatestPostedAt
+		}
+		return latest.UTC().Format(time.RFC3339Nano)
+	},
+	ParseKey: parseTimeKey,
+}
+
+var SortAuthorName = AuthorSortOrder{
+	Name: "name",
+	Key: func(author models.Author) string {
+		return author.Name
+	},
+	ParseKey: parseStringKey,
+}
+
+// AuthorSorts are the sort orders author listings accept
+var AuthorSorts = map[string]AuthorSortOrder{
+	SortMostArticles.Name: SortMostArticles,
+	SortLatestPost.Name:   SortLatestPost,
+	SortAuthorName.Name:   SortAuthorName,
+}
+
+// AuthorFilter narrows author listings, Search matches part of the
+// name case-insensitively
+type AuthorFilter struct {
+	Search string
+}
+
+type AuthorPage struct {
+	Authors []models.Author
+	PageInfo
+}
+
+func authorID(author models.Author) string { return author.ID }
+
+// NewAuthorPage builds the page from the authors fetched for it, see
+// NewPage
+func NewAuthorPage(authors []models.Author, total int64, sort AuthorSortOrder, page PageRequest) AuthorPage {
+	authors, info := NewPage(authors, total, authorID, sort, page)
+	return AuthorPage{Authors: authors, PageInfo: info}
+}
+
+type TagCount struct {
+	Tag   string `gorm:"column:tag" json:"tag"`
+	Count int64  `gorm:"column:count" json:"count"`
+}
+
+// AuthorStats summarises an author's articles. AverageReadMinutes
+// skips articles without a read duration
+type AuthorStats struct {
+	ArticleCount       int64      `gorm:"column:articleCount" json:"articleCount"`
+	FirstPostedAt      *time.Time `gorm:"column:firstPostedAt" json:"firstPostedAt"`
+	LastPostedAt       *time.Time `gorm:"column:lastPostedAt" json:"lastPostedAt"`
+	AverageReadMinutes float64    `gorm:"column:averageReadMinutes" json:"averageReadMinutes"`
+	Tags               []TagCount `gorm:"-" json:"tags"`
+}
@@ -0,0 +1,1579 @@
+// Package contract holds the behaviour every repository backend
+// must share, the tests of each backend run it against their
+// repositories with one subtest per case
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+type Case struct {
+	Name string
+	Run  func(repos repository.Repositories) error
+}
+
+// Run executes every case as a subtest of t named after the case,
+// against fresh repositories returned by newRepos
+func Run(t *testing.T, newRepos func() (repository.Repositories, error)) {
+	for _, c := range Cases {
+		t.Run(c.Name, func(t *testing.T) {
+			repos, err := newRepos()
+			if err != nil {
+				t.Fatalf("failed to create repositories: %v", err)
+			}
+			if err := c.Run(repos); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func day(year int, month time.Month, date, hour int) time.Time {
+	return time.Date(year, month, date, hour, 0, 0, 0, time.UTC)
+}
+
+func seedAuthor(repos repository.Repositories, name string) (models.Author, error) {
+	return repos.Authors.Create(models.Author{
+		Name:      name,
+		AvatarUrl: "https://example.com/" + strings.ToLower(name) + ".png",
+		PageUrl:   "https://hackernoon.com/u/" + strings.ToLower(name),
+	})
+}
+
+func seedArticle(repos repository.Repositories, authorID, title, tagIndex string, postedAt time.Time) (models.Article, error) {
+	return repos.Articles.Create(models.Article{
+		AuthorID:     authorID,
+		Tag:          "#bitcoin",
+		TagIndex:     tagIndex,
+		Title:        title,
+		Href:         "https://hackernoon.com/" + tagIndex,
+		ImageUrl:     "https://example.com/" + tagIndex + ".png",
+		PostedAt:     postedAt,
+		ReadDuration: "5m",
+	})
+}
+
+// seedDays creates one author with articles on 3 Jan (two),
+// 2 Jan (none) and 1 Jan (one)
+func seedDays(repos repository.Repositories) (models.Author, []models.Article, error) {
+	author, err := seedAuthor(repos, "Satoshi")
+	if err != nil {
+		return author, nil, err
+	}
+
+	var articles []models.Article
+	for _, seed := range []struct {
+		title    string
+		tagIndex string
+		postedAt time.Time
+	}{
+		{"Bitcoin Whitepaper", "a1", day(2021, time.January, 1, 12)},
+		{"Elliptic Curve Crypto Intro", "a2", day(2021, time.January, 3, 9)},
+		{"Lightning Network Basics", "a3", day(2021, time.January, 3, 15)},
+	} {
+		article, err := seedArticle(repos, author.ID, seed.title, seed.tagIndex, seed.postedAt)
+		if err != nil {
+			return author, nil, err
+		}
+		articles = append(articles, article)
+	}
+
+	return author, articles, nil
+}
+
+// nextPage follows an encoded cursor of the newest first listing
+func nextPage(repos repository.Repositories, filter repository.ArticleFilter, encoded string,
+	limit int) (repository.ArticlePage, error) {
+	cursor, err := repository.DecodeCursor(encoded, repository.SortNewest)
+	if err != nil {
+		return repository.ArticlePage{}, fmt.Errorf("decoding cursor %q: %w", encoded, err)
+	}
+	return repos.Articles.FindPage(filter, repository.SortNewest, repository.PageRequest{Limit: limit, Cursor: cursor})
+}
+
+func titles(articles []models.Article) []string {
+	var titles []string
+	for _, article := range articles {
+		titles = append(titles, article.Title)
+	}
+	return titles
+}
+
+func expectTitles(articles []models.Article, want ...string) error {
+	got := titles(articles)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		return fmt.Errorf("got titles %q, want %q", got, want)
+	}
+	return nil
+}
+
+var Cases = []Case{
+	{
+		Name: "authors are created and found by id, name and page",
+		Run: func(repos repository.Repositories) error {
+			author, err := seedAuthor(repos, "Satoshi")
+			if err != nil {
+				return err
+			}
+			if author.ID == "" {
+				return fmt.Errorf("created author has no id")
+			}
+
+			byID, err := repos.Authors.FindOne(author.ID)
+			if err != nil || byID.Name != "Satoshi" {
+				return fmt.Errorf("FindOne = %+v, %v", byID, err)
+			}
+			byName, err := repos.Authors.FindByName("Satoshi")
+			if err != nil || byName.ID != author.ID {
+				return fmt.Errorf("FindByName = %+v, %v", byName, err)
+			}
+			byPage, err := repos.Authors.FindByPage(author.PageUrl)
+			if err != nil || byPage.ID != author.ID {
+				return fmt.Errorf("FindByPage = %+v, %v", byPage, err)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "author names are unique",
+		Run: func(repos repository.Repositories) error {
+			if _, err := seedAuthor(repos, "Satoshi"); err != nil {
+				return err
+			}
+			if _, err := seedAuthor(repos, "Satoshi"); !errors.Is(err, models.ErrConflict) {
+				return fmt.Errorf("duplicate author error = %v, want models.ErrConflict", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "missing records return models.ErrNotFound",
+		Run: func(repos repository.Repositories) error {
+			if _, err := repos.Articles.FindOne("missing"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("Articles.FindOne error = %v", err)
+			}
+			if _, err := repos.Articles.FindByTitle("missing"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("Articles.FindByTitle error = %v", err)
+			}
+			if err := repos.Articles.Delete("missing"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("Articles.Delete error = %v", err)
+			}
+			if _, err := repos.Authors.FindOne("missing"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("Authors.FindOne error = %v", err)
+			}
+			if _, err := repos.Authors.FindByName("missing"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("Authors.FindByName error = %v", err)
+			}
+			if _, err := repos.FileRecords.FindByFilename("missing"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("FileRecords.FindByFilename error = %v", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "articles require an existing author",
+		Run: func(repos repository.Repositories) error {
+			_, err := seedArticle(repos, "00000000-0000-0000-0000-000000000000", "Orphan", "a1", time.Now())
+			if err == nil {
+				return fmt.Errorf("expected an error creating an article without an author")
+			}
+			return nil
+		},
+	},
+	{
+		Name: "articles are listed newest first with their author",
+		Run: func(repos repository.Repositories) error {
+			author, _, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+
+			page, err := repos.Articles.FindPage(repository.ArticleFilter{}, repository.SortNewest,
+				repository.PageRequest{Limit: 2})
+			if err != nil {
+				return err
+			}
+			if page.Total != 3 {
+				return fmt.Errorf("Total = %d, want 3", page.Total)
+			}
+			if page.PrevCursor != "" {
+				return fmt.Errorf("first page has a previous cursor")
+			}
+			if err := expectTitles(page.Articles, "Lightning Network Basics", "Elliptic Curve Crypto Intro"); err != nil {
+				return err
+			}
+			for _, article := range page.Articles {
+				if article.Author == nil || article.Author.ID != author.ID {
+					return fmt.Errorf("article %q has no author loaded", article.Title)
+				}
+			}
+
+			next, err := nextPage(repos, repository.ArticleFilter{}, page.NextCursor, 2)
+			if err != nil {
+				return err
+			}
+			if next.Total != 3 || next.NextCursor != "" {
+				return fmt.Errorf("last page Total = %d with next cursor %q", next.Total, next.NextCursor)
+			}
+			return expectTitles(next.Articles, "Bitcoin Whitepaper")
+		},
+	},
+	{
+		Name: "keyset pages keep articles posted at the same time",
+		Run: func(repos repository.Repositories) error {
+			author, err := seedAuthor(repos, "Satoshi")
+			if err != nil {
+				return err
+			}
+			var want []string
+			for i := 1; i <= 5; i++ {
+				title := fmt.Sprintf("Midnight Article %d", i)
+				if _, err := seedArticle(repos, author.ID, title, fmt.Sprintf("a%d", i), day(2021, time.January, 1, 0)); err != nil {
+					return err
+				}
+				want = append(want, title)
+			}
+
+			var got []string
+			var pages []repository.ArticlePage
+			page, err := repos.Articles.FindPage(repository.ArticleFilter{}, repository.SortNewest,
+				repository.PageRequest{Limit: 2})
+			for {
+				if err != nil {
+					return err
+				}
+				pages = append(pages, page)
+				got = append(got, titles(page.Articles)...)
+				if page.NextCursor == "" {
+					break
+				}
+				page, err = nextPage(repos, repository.ArticleFilter{}, page.NextCursor, 2)
+			}
+
+			sort.Strings(got)
+			if strings.Join(got, "|") != strings.Join(want, "|") {
+				return fmt.Errorf("walked titles %q, want each of %q once", got, want)
+			}
+
+			// Walking back from the last page returns the same pages
+			for i := len(pages) - 1; i > 0; i-- {
+				prev, err := nextPage(repos, repository.ArticleFilter{}, pages[i].PrevCursor, 2)
+				if err != nil {
+					return err
+				}
+				if err := expectTitles(prev.Articles, titles(pages[i-1].Articles)...); err != nil {
+					return fmt.Errorf("previous page of page %d: %w", i+1, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Name: "article listings filter by author, tag and posted date",
+		Run: func(repos repository.Repositories) error {
+			author, _, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+			other, err := seedAuthor(repos, "Hal")
+			if err != nil {
+				return err
+			}
+			if _, err := seedArticle(repos, other.ID, "Running Bitcoin", "a4", day(2021, time.January, 2, 8)); err != nil {
+				return err
+			}
+
+			for _, c := range []struct {
+				filter repository.ArticleFilter
+				want   []string
+			}{
+				{repository.ArticleFilter{AuthorID: other.ID}, []string{"Running Bitcoin"}},
+				{repository.ArticleFilter{AuthorID: author.ID, PostedTo: day(2021, time.January, 2, 23)},
+					[]string{"Bitcoin Whitepaper"}},
+				{repository.ArticleFilter{Tag: "#bitcoin", PostedTo: day(2021, time.January, 2, 23)},
+					[]string{"Running Bitcoin", "Bitcoin Whitepaper"}},
+				{repository.ArticleFilter{Tag: "#ethereum"}, nil},
+			} {
+				page, err := repos.Articles.FindPage(c.filter, repository.SortNewest, repository.PageRequest{Limit: 10})
+				if err != nil {
+					return err
+				}
+				if page.Total != int64(len(c.want)) {
+					return fmt.Errorf("FindPage(%+v) Total = %d, want %d", c.filter, page.Total, len(c.want))
+				}
+				if err := expectTitles(page.Articles, c.want...); err != nil {
+					return fmt.Errorf("FindPage(%+v): %w", c.filter, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Name: "article listings combine read duration, author name, image and link filters",
+		Run: func(repos repository.Repositories) error {
+			author, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+
+			long, err := seedArticle(repos, author.ID, "Proof of Work Explained", "a4", day(2021, time.March, 5, 10))
+			if err != nil {
+				return err
+			}
+			long.ReadDuration = "1h 12m"
+			long.Href = ""
+			long.SourceTag = "cryptography"
+			if _, err := repos.Articles.Update(long); err != nil {
+				return err
+			}
+			articles[0].ReadDuration = "14m"
+			articles[0].ImageUrl = ""
+			if _, err := repos.Articles.Update(articles[0]); err != nil {
+				return err
+			}
+
+			yes, no := true, false
+			for _, c := range []struct {
+				filter repository.ArticleFilter
+				want   []string
+			}{
+				{repository.ArticleFilter{AuthorName: "satoshi", MinReadMinutes: 10,
+					PostedFrom: day(2021, time.January, 1, 0), PostedTo: day(2021, time.December, 31, 23)},
+					[]string{"Proof of Work Explained", "Bitcoin Whitepaper"}},
+				{repository.ArticleFilter{AuthorName: "Hal"}, nil},
+				{repository.ArticleFilter{MaxReadMinutes: 5}, []string{"Lightning Network Basics", "Elliptic Curve Crypto Intro"}},
+				{repository.ArticleFilter{HasImage: &no}, []string{"Bitcoin Whitepaper"}},
+				{repository.ArticleFilter{HasLink: &no}, []string{"Proof of Work Explained"}},
+				{repository.ArticleFilter{HasLink: &yes, HasImage: &yes}, []string{"Lightning Network Basics", "Elliptic Curve Crypto Intro"}},
+				{repository.ArticleFilter{SourceTag: "cryptography"}, []string{"Proof of Work Explained"}},
+			} {
+				page, err := repos.Articles.FindPage(c.filter, repository.SortNewest, repository.PageRequest{Limit: 10})
+				if err != nil {
+					return err
+				}
+				if page.Total != int64(len(c.want)) {
+					return fmt.Errorf("FindPage(%+v) Total = %d, want %d", c.filter, page.Total, len(c.want))
+				}
+				if err := expectTitles(page.Articles, c.want...); err != nil {
+					return fmt.Errorf("FindPage(%+v): %w", c.filter, err)
+				}
+			}
+
+			for _, c := range []struct {
+				sort repository.SortOrder
+				want []string
+			}{
+				{repository.SortOldest, []string{"Bitcoin Whitepaper", "Elliptic Curve Crypto Intro",
+					"Lightning Network Basics", "Proof of Work Explained"}},
+				{repository.SortLongestRead, []string{"Proof of Work Explained", "Bitcoin Whitepaper"}},
+				{repository.SortTitle, []string{"Bitcoin Whitepaper", "Elliptic Curve Crypto Intro",
+					"Lightning Network Basics", "Proof of Work Explained"}},
+			} {
+				// Pages of one follow the cursors through every tie
+				var got []string
+				page, err := repos.Articles.FindPage(repository.ArticleFilter{}, c.sort, repository.PageRequest{Limit: 1})
+				for {
+					if err != nil {
+						return err
+					}
+					got = append(got, titles(page.Articles)...)
+					if page.NextCursor == "" {
+						break
+					}
+					cursor, err := repository.DecodeCursor(page.NextCursor, c.sort)
+					if err != nil {
+						return err
+					}
+					page, err = repos.Articles.FindPage(repository.ArticleFilter{}, c.sort,
+						repository.PageRequest{Limit: 1, Cursor: cursor})
+				}
+				if len(got) != 4 {
+					return fmt.Errorf("%s sort walked %q, want 4 articles", c.sort.Name, got)
+				}
+				if strings.Join(got[:len(c.want)], "|") != strings.Join(c.want, "|") {
+					return fmt.Errorf("%s sort walked %q, want it to start with %q", c.sort.Name, got, c.want)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Name: "articles are found by tag index with their neighbours and count clicks",
+		Run: func(repos repository.Repositories) error {
+			_, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+
+			article, err := repos.Articles.FindByTagIndex("a2")
+			if err != nil {
+				return err
+			}
+			if article.ID != articles[1].ID || article.Author == nil {
+				return fmt.Errorf("FindByTagIndex(a2) = %q with author %v, want %q with its author",
+					article.Title, article.Author, articles[1].Title)
+			}
+			if _, err := repos.Articles.FindByTagIndex("a99"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("FindByTagIndex(a99) error = %v, want ErrNotFound", err)
+			}
+
+			for _, c := range []struct {
+				article        models.Article
+				previous, next string
+			}{
+				{articles[0], "", "Elliptic Curve Crypto Intro"},
+				{articles[1], "Bitcoin Whitepaper", "Lightning Network Basics"},
+				{articles[2], "Elliptic Curve Crypto Intro", ""},
+			} {
+				neighbours, err := repos.Articles.FindNeighbours(c.article)
+				if err != nil {
+					return err
+				}
+				var previous, next string
+				if neighbours.Previous != nil {
+					previous = neighbours.Previous.Title
+				}
+				if neighbours.Next != nil {
+					next = neighbours.Next.Title
+				}
+				if previous != c.previous || next != c.next {
+					return fmt.Errorf("FindNeighbours(%q) = (%q, %q), want (%q, %q)",
+						c.article.Title, previous, next, c.previous, c.next)
+				}
+			}
+
+			for i := 0; i < 2; i++ {
+				if err := repos.Articles.IncrementClickCount(article.ID); err != nil {
+					return err
+				}
+			}
+			// Saving a stale copy keeps the clicks
+			if _, err := repos.Articles.Update(articles[1]); err != nil {
+				return err
+			}
+			clicked, err := repos.Articles.FindOne(article.ID)
+			if err != nil {
+				return err
+			}
+			if clicked.ClickCount != 2 {
+				return fmt.Errorf("ClickCount = %d, want 2", clicked.ClickCount)
+			}
+			if err := repos.Articles.IncrementClickCount("00000000-0000-0000-0000-000000000000"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("IncrementClickCount(missing) error = %v, want ErrNotFound", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "authors are listed by article count, latest post and name with stats",
+		Run: func(repos repository.Repositories) error {
+			satoshi, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+			hal, err := seedAuthor(repos, "Hal")
+			if err != nil {
+				return err
+			}
+			if _, err := seedArticle(repos, hal.ID, "Running Bitcoin", "a4", day(2021, time.January, 5, 8)); err != nil {
+				return err
+			}
+			if _, err := seedAuthor(repos, "Nick"); err != nil {
+				return err
+			}
+			articles[2].Tag = "#lightning"
+			articles[2].ReadDuration = "8m"
+			if _, err := repos.Articles.Update(articles[2]); err != nil {
+				return err
+			}
+
+			names := func(authors []models.Author) string {
+				var names []string
+				for _, author := range authors {
+					names = append(names, author.Name)
+				}
+				return strings.Join(names, "|")
+			}
+
+			for _, c := range []struct {
+				filter repository.AuthorFilter
+				sort   repository.AuthorSortOrder
+				want   string
+			}{
+				{repository.AuthorFilter{}, repository.SortMostArticles, "Satoshi|Hal|Nick"},
+				{repository.AuthorFilter{}, repository.SortLatestPost, "Hal|Satoshi|Nick"},
+				{repository.AuthorFilter{}, repository.SortAuthorName, "Hal|Nick|Satoshi"},
+				{repository.AuthorFilter{Search: "A"}, repository.SortAuthorName, "Hal|Satoshi"},
+				{repository.AuthorFilter{Search: "%"}, repository.SortAuthorName, ""},
+			} {
+				// Pages of one follow the cursors
+				var got []models.Author
+				page, err := repos.Authors.FindPage(c.filter, c.sort, repository.PageRequest{Limit: 1})
+				for {
+					if err != nil {
+						return err
+					}
+					got = append(got, page.Authors...)
+					if page.NextCursor == "" {
+						break
+					}
+					cursor, err := repository.DecodeCursor(page.NextCursor, c.sort)
+					if err != nil {
+						return err
+					}
+					page, err = repos.Authors.FindPage(c.filter, c.sort, repository.PageRequest{Limit: 1, Cursor: cursor})
+				}
+				if names(got) != c.want {
+					return fmt.Errorf("FindPage(%+v, %s) walked %q, want %q", c.filter, c.sort.Name, names(got), c.want)
+				}
+				for _, author := range got {
+					if author.Name == "Satoshi" && author.ArticleCount != 3 {
+						return fmt.Errorf("Satoshi ArticleCount = %d, want 3", author.ArticleCount)
+					}
+				}
+			}
+
+			stats, err := repos.Authors.FindStats(satoshi.ID)
+			if err != nil {
+				return err
+			}
+			if stats.ArticleCount != 3 || stats.AverageReadMinutes != 6 ||
+				stats.FirstPostedAt == nil || !stats.FirstPostedAt.Equal(day(2021, time.January, 1, 12)) ||
+				stats.LastPostedAt == nil || !stats.LastPostedAt.Equal(day(2021, time.January, 3, 15)) {
+				return fmt.Errorf("FindStats = %+v, want 3 articles averaging 6 minutes from 1 to 3 Jan", stats)
+			}
+			if len(stats.Tags) != 2 || stats.Tags[0] != (repository.TagCount{Tag: "#bitcoin", Count: 2}) {
+				return fmt.Errorf("FindStats tags = %+v, want #bitcoin twice then #lightning", stats.Tags)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "tags are normalized, linked to articles and listed with counts",
+		Run: func(repos repository.Repositories) error {
+			_, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+
+			bitcoin, err := repos.Tags.FindOrCreate("#Bitcoin")
+			if err != nil {
+				return err
+			}
+			if bitcoin.Slug != "bitcoin" || bitcoin.Name != "Bitcoin" {
+				return fmt.Errorf("FindOrCreate(#Bitcoin) = %q named %q, want bitcoin named Bitcoin", bitcoin.Slug, bitcoin.Name)
+			}
+			same, err := repos.Tags.FindOrCreate("bitcoin")
+			if err != nil {
+				return err
+			}
+			if same.ID != bitcoin.ID {
+				return fmt.Errorf("FindOrCreate(bitcoin) created a second tag")
+			}
+			for _, spelling := range []string{"#bitcoin", "#BITCOIN", "Bitcoin"} {
+				if same, err = repos.Tags.FindOrCreate(spelling); err != nil {
+					return err
+				}
+			}
+			if fmt.Sprint(same.Aliases) != "[bitcoin BITCOIN]" {
+				return fmt.Errorf("Bitcoin aliases = %v, want [bitcoin BITCOIN]", same.Aliases)
+			}
+			aliased, err := repos.Tags.FindBySlug("BITCOIN")
+			if err != nil {
+				return err
+			}
+			if aliased.ID != bitcoin.ID || len(aliased.Aliases) != 2 {
+				return fmt.Errorf("FindBySlug(BITCOIN) = %+v, want the Bitcoin tag with its aliases", aliased)
+			}
+			web3, err := repos.Tags.FindOrCreate("#Web 3")
+			if err != nil {
+				return err
+			}
+			if web3.Slug != "web-3" {
+				return fmt.Errorf("FindOrCreate(#Web 3) slug = %q, want web-3", web3.Slug)
+			}
+			if _, err := repos.Tags.FindOrCreate("#"); err == nil {
+				return fmt.Errorf("FindOrCreate(#) succeeded, want an error for a tag without a slug")
+			}
+
+			for _, article := range articles {
+				if err := repos.Tags.SetArticleTags(article.ID, []string{bitcoin.ID}); err != nil {
+					return err
+				}
+			}
+			// Replacing the tags drops the ones not given
+			if err := repos.Tags.SetArticleTags(articles[2].ID, []string{web3.ID}); err != nil {
+				return err
+			}
+			tags, err := repos.Tags.FindByArticle(articles[2].ID)
+			if err != nil {
+				return err
+			}
+			if len(tags) != 1 || tags[0].ID != web3.ID {
+				return fmt.Errorf("FindByArticle = %+v, want only web-3", tags)
+			}
+
+			tagPage, err := repos.Tags.FindPage(repository.TagFilter{}, repository.SortMostTagged, repository.PageRequest{Limit: 10})
+			if err != nil {
+				return err
+			}
+			if len(tagPage.Tags) != 2 || tagPage.Tags[0].Slug != "bitcoin" || tagPage.Tags[0].ArticleCount != 2 ||
+				tagPage.Tags[1].ArticleCount != 1 {
+				return fmt.Errorf("FindPage = %+v, want bitcoin with 2 articles then web-3 with 1", tagPage.Tags)
+			}
+			searched, err := repos.Tags.FindPage(repository.TagFilter{Search: "WEB"}, repository.SortTagSlug, repository.PageRequest{Limit: 10})
+			if err != nil {
+				return err
+			}
+			if searched.Total != 1 || len(searched.Tags) != 1 || searched.Tags[0].ID != web3.ID {
+				return fmt.Errorf("FindPage(WEB) = %+v, want web-3", searched.Tags)
+			}
+
+			page, err := repos.Articles.FindPage(repository.ArticleFilter{TagSlug: "bitcoin"}, repository.SortNewest,
+				repository.PageRequest{Limit: 10})
+			if err != nil {
+				return err
+			}
+			if err := expectTitles(page.Articles, "Elliptic Curve Crypto Intro", "Bitcoin Whitepaper"); err != nil {
+				return fmt.Errorf("FindPage(bitcoin tag): %w", err)
+			}
+
+			if err := repos.Articles.Delete(articles[2].ID); err != nil {
+				return err
+			}
+			if tags, err := repos.Tags.FindByArticle(articles[2].ID); err != nil || len(tags) != 0 {
+				return fmt.Errorf("FindByArticle(deleted) = %+v, %v, want no tags", tags, err)
+			}
+			if _, err := repos.Tags.FindBySlug("#WEB-3"); err != nil {
+				return fmt.Errorf("FindBySlug(#WEB-3): %w", err)
+			}
+			if _, err := repos.Tags.FindBySlug("ethereum"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("FindBySlug(ethereum) error = %v, want ErrNotFound", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "articles are listed oldest first",
+		Run: func(repos repository.Repositories) error {
+			if _, _, err := seedDays(repos); err != nil {
+				return err
+			}
+
+			articles, count, err := repos.Articles.FindAllByPostedAtInAsc(10)
+			if err != nil {
+				return err
+			}
+			if count != 3 {
+				return fmt.Errorf("count = %d, want 3", count)
+			}
+			return expectTitles(articles, "Bitcoin Whitepaper", "Elliptic Curve Crypto Intro", "Lightning Network Basics")
+		},
+	},
+	{
+		Name: "search ranks title matches first and matches tag indexes exactly",
+		Run: func(repos repository.Repositories) error {
+			if _, _, err := seedDays(repos); err != nil {
+				return err
+			}
+
+			// Every seeded article is tagged #bitcoin, only one has it in the title
+			page, err := repos.Articles.Search(repository.SearchOptions{
+				Query: "BITCOIN",
+				Page:  repository.PageRequest{Limit: 10},
+			})
+			if err != nil {
+				return err
+			}
+			articles, count := page.Articles, page.Total
+			if count != 3 || len(articles) != 3 {
+				return fmt.Errorf("Search count = %d with %d articles, want 3", count, len(articles))
+			}
+			if articles[0].Title != "Bitcoin Whitepaper" {
+				return fmt.Errorf("Search ranked %q first, want %q", articles[0].Title, "Bitcoin Whitepaper")
+			}
+			if articles[0].SearchRank <= articles[1].SearchRank {
+				return fmt.Errorf("Search ranks = %v, %v, want the title match ranked higher",
+					articles[0].SearchRank, articles[1].SearchRank)
+			}
+			if !strings.Contains(articles[0].SearchHighlight, "<mark>Bitcoin</mark>") {
+				return fmt.Errorf("Search highlight = %q, want <mark>Bitcoin</mark>", articles[0].SearchHighlight)
+			}
+			if articles[0].Author == nil {
+				return fmt.Errorf("Search didn't load the author")
+			}
+
+			articles, count, err = repos.Articles.SearchByTagIndex("a2")
+			if err != nil {
+				return err
+			}
+			if count != 1 {
+				return fmt.Errorf("SearchByTagIndex count = %d, want 1", count)
+			}
+			return expectTitles(articles, "Elliptic Curve Crypto Intro")
+		},
+	},
+	{
+		Name: "search highlights escape scraped HTML",
+		Run: func(repos repository.Repositories) error {
+			author, err := seedAuthor(repos, "Satoshi")
+			if err != nil {
+				return err
+			}
+			if _, err := repos.Articles.Create(models.Article{
+				AuthorID:     author.ID,
+				Tag:          "#bitcoin",
+				TagIndex:     "x1",
+				Title:        "Bitcoin <script>alert(1)</script>",
+				ImageUrl:     "https://example.com/x1.png",
+				PostedAt:     day(2024, time.January, 1, 9),
+				ReadDuration: "5m",
+				Body:         `Mining <img src=x onerror="alert(1)"> rewards`,
+			}); err != nil {
+				return err
+			}
+
+			page, err := repos.Articles.Search(repository.SearchOptions{
+				Query: "bitcoin mining",
+				Page:  repository.PageRequest{Limit: 10},
+			})
+			if err != nil {
+				return err
+			}
+			if len(page.Articles) != 1 {
+				return fmt.Errorf("Search returned %d articles, want 1", len(page.Articles))
+			}
+			highlight := page.Articles[0].SearchHighlight
+			if !strings.Contains(highlight, "<mark>Bitcoin</mark>") {
+				return fmt.Errorf("Search highlight = %q, want <mark>Bitcoin</mark>", highlight)
+			}
+			for _, raw := range []string{"<script", "<img", `"alert`} {
+				if strings.Contains(highlight, raw) {
+					return fmt.Errorf("Search highlight = %q, want %q escaped", highlight, raw)
+				}
+			}
+			if !strings.Contains(highlight, "&lt;script&gt;") {
+				return fmt.Errorf("Search highlight = %q, want the title's tags escaped", highlight)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "search supports author names, phrases and prefixes",
+		Run: func(repos repository.Repositories) error {
+			if _, _, err := seedDays(repos); err != nil {
+				return err
+			}
+
+			for _, c := range []struct {
+				query string
+				want  []string
+			}{
+				{"satoshi whitepaper", []string{"Bitcoin Whitepaper"}},
+				{"\"network basics\"", []string{"Lightning Network Basics"}},
+				{"\"basics network\"", nil},
+				{"ellip*", []string{"Elliptic Curve Crypto Intro"}},
+				{"\"lightning net*\"", []string{"Lightning Network Basics"}},
+				{"!!!", nil},
+			} {
+				page, err := repos.Articles.Search(repository.SearchOptions{
+					Query: c.query,
+					Page:  repository.PageRequest{Limit: 10},
+				})
+				if err != nil {
+					return fmt.Errorf("Search(%q): %w", c.query, err)
+				}
+				articles, count := page.Articles, page.Total
+				if count != int64(len(c.want)) {
+					return fmt.Errorf("Search(%q) count = %d, want %d", c.query, count, len(c.want))
+				}
+				if err := expectTitles(articles, c.want...); err != nil {
+					return fmt.Errorf("Search(%q): %w", c.query, err)
+				}
+			}
+
+			first, err := repos.Articles.Search(repository.SearchOptions{
+				Query: "bitcoin",
+				Page:  repository.PageRequest{Limit: 1},
+			})
+			if err != nil {
+				return err
+			}
+			cursor, err := repository.DecodeCursor(first.NextCursor, repository.SortRelevance)
+			if err != nil {
+				return fmt.Errorf("search next cursor: %w", err)
+			}
+			second, err := repos.Articles.Search(repository.SearchOptions{
+				Query: "bitcoin",
+				Page:  repository.PageRequest{Limit: 1, Cursor: cursor},
+			})
+			if err != nil {
+				return err
+			}
+			if second.Total != 3 || len(second.Articles) != 1 {
+				return fmt.Errorf("second Search page Total = %d with %d articles, want 3 and 1",
+					second.Total, len(second.Articles))
+			}
+			if second.Articles[0].ID == first.Articles[0].ID {
+				return fmt.Errorf("second Search page repeats %q", first.Articles[0].Title)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "fuzzy search tolerates typos and suggests titles",
+		Run: func(repos repository.Repositories) error {
+			if _, _, err := seedDays(repos); err != nil {
+				return err
+			}
+
+			query := "eliptic curve crypto intro"
+			page, err := repos.Articles.Search(repository.SearchOptions{
+				Query: query,
+				Page:  repository.PageRequest{Limit: 10},
+			})
+			if err != nil {
+				return err
+			}
+			if len(page.Articles) != 0 {
+				return fmt.Errorf("full-text Search(%q) = %q, want no articles", query, titles(page.Articles))
+			}
+
+			page, err = repos.Articles.Search(repository.SearchOptions{
+				Query:          query,
+				Fuzzy:          true,
+				FuzzyThreshold: 0.3,
+				Page:           repository.PageRequest{Limit: 10},
+			})
+			if err != nil {
+				return err
+			}
+			articles, count := page.Articles, page.Total
+			if count != 1 {
+				return fmt.Errorf("fuzzy Search(%q) count = %d, want 1", query, count)
+			}
+			if err := expectTitles(articles, "Elliptic Curve Crypto Intro"); err != nil {
+				return err
+			}
+
+			suggestion, err := repos.Articles.DidYouMean("lightnin netwrk", 0.2)
+			if err != nil {
+				return err
+			}
+			if suggestion != "Lightning Network Basics" {
+				return fmt.Errorf("DidYouMean = %q, want %q", suggestion, "Lightning Network Basics")
+			}
+
+			suggestion, err = repos.Articles.DidYouMean("zzzz", 0.2)
+			if err != nil {
+				return err
+			}
+			if suggestion != "" {
+				return fmt.Errorf("DidYouMean(%q) = %q, want no suggestion", "zzzz", suggestion)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "articles are found by the day they were posted",
+		Run: func(repos repository.Repositories) error {
+			if _, _, err := seedDays(repos); err != nil {
+				return err
+			}
+
+			articles, err := repos.Articles.FindByPostedAt(day(2021, time.January, 3, 0), time.UTC)
+			if err != nil {
+				return err
+			}
+			return expectTitles(articles, "Lightning Network Basics", "Elliptic Curve Crypto Intro")
+		},
+	},
+	{
+		Name: "day counts fill the days without articles",
+		Run: func(repos repository.Repositories) error {
+			if _, _, err := seedDays(repos); err != nil {
+				return err
+			}
+
+			dayCounts, err := repos.Articles.FindArticleCountPerDay(10, time.Time{}, time.UTC)
+			if err != nil {
+				return err
+			}
+			got := fmt.Sprint(dayCounts)
+			want := fmt.Sprint([]map[string]interface{}{
+				{"date": "2021-01-03", "count": int64(2)},
+				{"date": "2021-01-02", "count": int64(0)},
+				{"date": "2021-01-01", "count": int64(1)},
+			})
+			if got != want {
+				return fmt.Errorf("day counts = %s, want %s", got, want)
+			}
+
+			var totalDays int64
+			if err := repos.Articles.CountDistinctDays(&totalDays, time.UTC); err != nil {
+				return err
+			}
+			if totalDays != 2 {
+				return fmt.Errorf("CountDistinctDays = %d, want 2", totalDays)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "days are bucketed in the requested time zone",
+		Run: func(repos repository.Repositories) error {
+			if _, _, err := seedDays(repos); err != nil {
+				return err
+			}
+			// 14 hours ahead of UTC, moving every article to a later day
+			kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+			if err != nil {
+				return err
+			}
+
+			dayCounts, err := repos.Articles.FindArticleCountPerDay(10, time.Time{}, kiritimati)
+			if err != nil {
+				return err
+			}
+			got := fmt.Sprint(dayCounts)
+			want := fmt.Sprint([]map[string]interface{}{
+				{"date": "2021-01-04", "count": int64(1)},
+				{"date": "2021-01-03", "count": int64(1)},
+				{"date": "2021-01-02", "count": int64(1)},
+			})
+			if got != want {
+				return fmt.Errorf("day counts = %s, want %s", got, want)
+			}
+
+			dayCounts, err = repos.Articles.FindArticleCountPerDay(10, day(2021, time.January, 4, 0), kiritimati)
+			if err != nil {
+				return err
+			}
+			if len(dayCounts) != 2 || dayCounts[0]["date"] != "2021-01-03" {
+				return fmt.Errorf("day counts before 4 Jan = %v, want 3 and 2 Jan", dayCounts)
+			}
+
+			var totalDays int64
+			if err := repos.Articles.CountDistinctDays(&totalDays, kiritimati); err != nil {
+				return err
+			}
+			if totalDays != 3 {
+				return fmt.Errorf("CountDistinctDays = %d, want 3", totalDays)
+			}
+
+			// The day is taken as written, whatever zone the time carries
+			articles, err := repos.Articles.FindByPostedAt(time.Date(2021, time.January, 4, 23, 0, 0, 0, kiritimati), kiritimati)
+			if err != nil {
+				return err
+			}
+			return expectTitles(articles, "Lightning Network Basics")
+		},
+	},
+	{
+		Name: "timeseries buckets are zero filled and grouped by author or tag",
+		Run: func(repos repository.Repositories) error {
+			satoshi, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+			hal, err := seedAuthor(repos, "Hal")
+			if err != nil {
+				return err
+			}
+			if _, err := seedArticle(repos, hal.ID, "Running Bitcoin", "a4", day(2021, time.February, 10, 8)); err != nil {
+				return err
+			}
+
+			bitcoin, err := repos.Tags.FindOrCreate("bitcoin")
+			if err != nil {
+				return err
+			}
+			lightning, err := repos.Tags.FindOrCreate("lightning")
+			if err != nil {
+				return err
+			}
+			for _, article := range articles {
+				tagIDs := []string{bitcoin.ID}
+				if article.Title == "Lightning Network Basics" {
+					tagIDs = append(tagIDs, lightning.ID)
+				}
+				if err := repos.Tags.SetArticleTags(article.ID, tagIDs); err != nil {
+					return err
+				}
+			}
+
+			for _, test := range []struct {
+				options repository.TimeseriesOptions
+				want    string
+			}{
+				{repository.TimeseriesOptions{Interval: repository.IntervalDay,
+					From: day(2021, time.January, 1, 0), To: day(2021, time.January, 4, 0)},
+					"all 3 [2021-01-01:1 2021-01-02:0 2021-01-03:2 2021-01-04:0]"},
+				// 1 Jan 2021 is a Friday, its week starts on 28 Dec
+				{repository.TimeseriesOptions{Interval: repository.IntervalWeek,
+					From: day(2021, time.January, 1, 0), To: day(2021, time.January, 12, 0)},
+					"all 3 [2020-12-28:3 2021-01-04:0 2021-01-11:0]"},
+				{repository.TimeseriesOptions{Interval: repository.IntervalMonth,
+					From: day(2020, time.December, 15, 0), To: day(2021, time.February, 1, 0)},
+					"all 4 [2020-12-01:0 2021-01-01:3 2021-02-01:1]"},
+				{repository.TimeseriesOptions{Interval: repository.IntervalYear,
+					From: day(2021, time.June, 1, 0), To: day(2021, time.June, 1, 0)},
+					"all 4 [2021-01-01:4]"},
+				{repository.TimeseriesOptions{Interval: repository.IntervalYear,
+					From: day(2019, time.January, 1, 0), To: day(2019, time.December, 31, 0)},
+					"all 0 [2019-01-01:0]"},
+				{repository.TimeseriesOptions{Interval: repository.IntervalMonth, GroupBy: repository.GroupByAuthor, GroupLimit: 10,
+					From: day(2021, time.January, 1, 0), To: day(2021, time.February, 1, 0)},
+					satoshi.ID + " 3 [2021-01-01:3 2021-02-01:0]|" + hal.ID + " 1 [2021-01-01:0 2021-02-01:1]"},
+				{repository.TimeseriesOptions{Interval: repository.IntervalMonth, GroupBy: repository.GroupByAuthor, GroupLimit: 1,
+					From: day(2021, time.January, 1, 0), To: day(2021, time.February, 1, 0)},
+					satoshi.ID + " 3 [2021-01-01:3 2021-02-01:0]"},
+				{repository.TimeseriesOptions{Interval: repository.IntervalDay, GroupBy: repository.GroupByTag, GroupLimit: 10,
+					From: day(2021, time.January, 2, 0), To: day(2021, time.January, 3, 0)},
+					"bitcoin 2 [2021-01-02:0 2021-01-03:2]|lightning 1 [2021-01-02:0 2021-01-03:1]"},
+			} {
+				test.options.Location = time.UTC
+				series, err := repos.Articles.FindTimeseries(test.options)
+				if err != nil {
+					return err
+				}
+
+				var got []string
+				for _, s := range series {
+					var points []string
+					for _, point := range s.Points {
+						points = append(points, fmt.Sprintf("%s:%d", point.Bucket, point.Count))
+					}
+					got = append(got, fmt.Sprintf("%s %d %v", s.Key, s.Total, points))
+				}
+				if strings.Join(got, "|") != test.want {
+					return fmt.Errorf("%s timeseries grouped by %q = %q, want %q",
+						test.options.Interval, test.options.GroupBy, strings.Join(got, "|"), test.want)
+				}
+			}
+
+			// Hal's article is on 10 Feb in UTC but still 9 Feb in Honolulu
+			honolulu, err := time.LoadLocation("Pacific/Honolulu")
+			if err != nil {
+				return err
+			}
+			series, err := repos.Articles.FindTimeseries(repository.TimeseriesOptions{Interval: repository.IntervalDay,
+				From: day(2021, time.February, 9, 0), To: day(2021, time.February, 10, 0), Location: honolulu})
+			if err != nil {
+				return err
+			}
+			if len(series) != 1 || series[0].Points[0].Count != 1 || series[0].Points[1].Count != 0 {
+				return fmt.Errorf("Honolulu timeseries = %+v, want the article on 9 Feb", series)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "day counts follow created, re-dated and deleted articles",
+		Run: func(repos repository.Repositories) error {
+			satoshi, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+			nairobi, err := time.LoadLocation("Africa/Nairobi")
+			if err != nil {
+				return err
+			}
+
+			expectDays := func(when string, want string) error {
+				for _, location := range []*time.Location{time.UTC, nairobi} {
+					dayCounts, err := repos.Articles.FindArticleCountPerDay(10, time.Time{}, location)
+					if err != nil {
+						return err
+					}
+					var totalDays int64
+					if err := repos.Articles.CountDistinctDays(&totalDays, location); err != nil {
+						return err
+					}
+					got := fmt.Sprintf("%v days %d", dayCounts, totalDays)
+					if got != want {
+						return fmt.Errorf("%s, %s day counts = %s, want %s", when, location, got, want)
+					}
+				}
+				return nil
+			}
+
+			// UTC is always rolled up, Nairobi once it's added. Other
+			// zones are counted from the articles
+			if err := repos.Articles.RebuildDayCounts([]string{"Africa/Nairobi"}); err != nil {
+				return err
+			}
+			if err := expectDays("after seeding",
+				"[map[count:2 date:2021-01-03] map[count:0 date:2021-01-02] map[count:1 date:2021-01-01]] days 2"); err != nil {
+				return err
+			}
+
+			hal, err := seedAuthor(repos, "Hal")
+			if err != nil {
+				return err
+			}
+			running, err := seedArticle(repos, hal.ID, "Running Bitcoin", "a4", day(2021, time.January, 2, 10))
+			if err != nil {
+				return err
+			}
+			if err := expectDays("after creating",
+				"[map[count:2 date:2021-01-03] map[count:1 date:2021-01-02] map[count:1 date:2021-01-01]] days 3"); err != nil {
+				return err
+			}
+
+			redated := articles[0]
+			redated.PostedAt = day(2021, time.January, 3, 11)
+			if _, err := repos.Articles.Update(redated); err != nil {
+				return err
+			}
+			if err := expectDays("after re-dating",
+				"[map[count:3 date:2021-01-03] map[count:1 date:2021-01-02]] days 2"); err != nil {
+				return err
+			}
+
+			if err := repos.Articles.Delete(articles[1].ID); err != nil {
+				return err
+			}
+			if err := repos.Authors.Delete(hal.ID); err != nil {
+				return err
+			}
+			if _, err := repos.Articles.FindOne(running.ID); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("article of a deleted author: err = %v, want models.ErrNotFound", err)
+			}
+			want := "[map[count:2 date:2021-01-03]] days 1"
+			if err := expectDays("after deleting", want); err != nil {
+				return err
+			}
+
+			if err := repos.Articles.RebuildDayCounts(nil); err != nil {
+				return err
+			}
+			if err := expectDays("after rebuilding", want); err != nil {
+				return err
+			}
+			if err := repos.Authors.Delete(satoshi.ID); err != nil {
+				return err
+			}
+			return expectDays("after deleting every article", "[] days 0")
+		},
+	},
+	{
+		Name: "author day counts are bucketed by day and author",
+		Run: func(repos repository.Repositories) error {
+			satoshi, _, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+			hal, err := seedAuthor(repos, "Hal")
+			if err != nil {
+				return err
+			}
+			// 3 Jan at 22:00 in UTC is already 4 Jan in Nairobi
+			if _, err := seedArticle(repos, hal.ID, "Running Bitcoin", "a4", day(2021, time.January, 3, 22)); err != nil {
+				return err
+			}
+			nairobi, err := time.LoadLocation("Africa/Nairobi")
+			if err != nil {
+				return err
+			}
+
+			for _, test := range []struct {
+				location *time.Location
+				want     []string
+			}{
+				{time.UTC, []string{"2021-01-01 Satoshi 1", "2021-01-03 Satoshi 2", "2021-01-03 Hal 1"}},
+				{nairobi, []string{"2021-01-01 Satoshi 1", "2021-01-03 Satoshi 2", "2021-01-04 Hal 1"}},
+			} {
+				dayCounts, err := repos.Articles.FindAuthorDayCounts(test.location)
+				if err != nil {
+					return err
+				}
+
+				var got []string
+				for _, dayCount := range dayCounts {
+					got = append(got, fmt.Sprintf("%s %s %d", dayCount.Day.Format(time.DateOnly), dayCount.AuthorName, dayCount.Count))
+				}
+				// Authors of the same day are ordered by id
+				if len(got) == 3 && got[1][:10] == got[2][:10] && satoshi.ID > hal.ID {
+					got[1], got[2] = got[2], got[1]
+				}
+				if strings.Join(got, "|") != strings.Join(test.want, "|") {
+					return fmt.Errorf("%s author day counts = %q, want %q", test.location, got, test.want)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Name: "streams hand out filtered articles newest first in batches",
+		Run: func(repos repository.Repositories) error {
+			_, _, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+			hal, err := seedAuthor(repos, "Hal")
+			if err != nil {
+				return err
+			}
+			if _, err := seedArticle(repos, hal.ID, "Running Bitcoin", "a4", day(2021, time.January, 2, 10)); err != nil {
+				return err
+			}
+
+			var batches [][]models.Article
+			err = repos.Articles.Stream(repository.ArticleFilter{}, 3, func(articles []models.Article) error {
+				batches = append(batches, slices.Clone(articles))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 1 {
+				return fmt.Errorf("streamed batches of %v, want 3 and 1 articles", batches)
+			}
+			if err := expectTitles(append(batches[0], batches[1]...), "Lightning Network Basics",
+				"Elliptic Curve Crypto Intro", "Running Bitcoin", "Bitcoin Whitepaper"); err != nil {
+				return err
+			}
+			for _, article := range batches[0] {
+				if article.Author == nil || article.Author.ID != article.AuthorID {
+					return fmt.Errorf("streamed article %q without its author", article.Title)
+				}
+			}
+
+			var streamed []models.Article
+			err = repos.Articles.Stream(repository.ArticleFilter{AuthorName: "hal"}, 3, func(articles []models.Article) error {
+				streamed = append(streamed, articles...)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if err := expectTitles(streamed, "Running Bitcoin"); err != nil {
+				return err
+			}
+
+			stop := errors.New("stop")
+			err = repos.Articles.Stream(repository.ArticleFilter{}, 1, func(articles []models.Article) error {
+				return stop
+			})
+			if !errors.Is(err, stop) {
+				return fmt.Errorf("Stream returned %v, want the error of handle", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "updates are persisted and counted",
+		Run: func(repos repository.Repositories) error {
+			_, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+
+			article := articles[0]
+			article.Href = "https://hackernoon.com/updated"
+			updated, err := repos.Articles.Update(article)
+			if err != nil {
+				return err
+			}
+			if updated.Href != article.Href {
+				return fmt.Errorf("updated href = %q, want %q", updated.Href, article.Href)
+			}
+
+			count, err := repos.Articles.FindCount()
+			if err != nil {
+				return err
+			}
+			if count != 3 {
+				return fmt.Errorf("FindCount = %d, want 3", count)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "articles without image metadata are paged by id and skipped once failed",
+		Run: func(repos repository.Repositories) error {
+			_, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+
+			withMetadata := articles[0]
+			withMetadata.ImageBlurHash = "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+			if _, err := repos.Articles.Update(withMetadata); err != nil {
+				return err
+			}
+			if err := repos.Articles.MarkImageMetadataFailed(articles[1].ID); err != nil {
+				return err
+			}
+			if err := repos.Articles.MarkImageMetadataFailed("00000000-0000-0000-0000-000000000000"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("MarkImageMetadataFailed of a missing article = %v, want ErrNotFound", err)
+			}
+
+			page, err := repos.Articles.FindAllWithoutImageMetadata("", 1)
+			if err != nil {
+				return err
+			}
+			if err := expectTitles(page, articles[2].Title); err != nil {
+				return err
+			}
+			page, err = repos.Articles.FindAllWithoutImageMetadata(page[0].ID, 1)
+			if err != nil {
+				return err
+			}
+			if len(page) != 0 {
+				return fmt.Errorf("page after the last article = %q, want none", titles(page))
+			}
+			return nil
+		},
+	},
+	{
+		Name: "deleting an author deletes their articles",
+		Run: func(repos repository.Repositories) error {
+			author, _, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+
+			if err := repos.Authors.Delete(author.ID); err != nil {
+				return err
+			}
+			count, err := repos.Articles.FindCount()
+			if err != nil {
+				return err
+			}
+			if count != 0 {
+				return fmt.Errorf("FindCount = %d after deleting the author, want 0", count)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "file records are unique by filename",
+		Run: func(repos repository.Repositories) error {
+			fileRecord := models.FileRecord{
+				URL:      "https://bucket.s3.region.amazonaws.com/uploads/a.png",
+				Filename: "uploads/a.png",
+			}
+			created, err := repos.FileRecords.Create(fileRecord)
+			if err != nil {
+				return err
+			}
+			found, err := repos.FileRecords.FindByFilename(fileRecord.Filename)
+			if err != nil || found.ID != created.ID {
+				return fmt.Errorf("FindByFilename = %+v, %v", found, err)
+			}
+			if _, err := repos.FileRecords.Create(fileRecord); !errors.Is(err, models.ErrConflict) {
+				return fmt.Errorf("duplicate file record error = %v, want models.ErrConflict", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "pending uploads are found by key and listed once expired",
+		Run: func(repos repository.Repositories) error {
+			now := time.Now()
+			for _, pendingUpload := range []models.PendingUpload{
+				{Key: "uploads/b.png", UploadedBy: "client", ContentType: "image/png", ExpiresAt: now.Add(-time.Minute)},
+				{Key: "uploads/a.png", UploadedBy: "client", ContentType: "image/png", ExpiresAt: now.Add(-time.Hour)},
+				{Key: "uploads/c.png", UploadedBy: "other", ContentType: "image/png", ExpiresAt: now.Add(time.Hour)},
+			} {
+				if _, err := repos.PendingUploads.Create(pendingUpload); err != nil {
+					return err
+				}
+			}
+
+			found, err := repos.PendingUploads.FindByKey("uploads/c.png")
+			if err != nil || found.UploadedBy != "other" {
+				return fmt.Errorf("FindByKey = %+v, %v", found, err)
+			}
+			duplicate := models.PendingUpload{Key: "uploads/c.png", UploadedBy: "client", ContentType: "image/png", ExpiresAt: now}
+			if _, err := repos.PendingUploads.Create(duplicate); !errors.Is(err, models.ErrConflict) {
+				return fmt.Errorf("duplicate pending upload error = %v, want models.ErrConflict", err)
+			}
+
+			expired, err := repos.PendingUploads.FindExpired(now, 10)
+			if err != nil {
+				return err
+			}
+			var keys []string
+			for _, pendingUpload := range expired {
+				keys = append(keys, pendingUpload.Key)
+			}
+			if strings.Join(keys, "|") != "uploads/a.png|uploads/b.png" {
+				return fmt.Errorf("FindExpired = %q, want the two expired uploads oldest first", keys)
+			}
+
+			if err := repos.PendingUploads.Delete("uploads/a.png"); err != nil {
+				return err
+			}
+			if _, err := repos.PendingUploads.FindByKey("uploads/a.png"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("FindByKey after Delete = %v, want models.ErrNotFound", err)
+			}
+			if err := repos.PendingUploads.Delete("uploads/a.png"); !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("second Delete = %v, want models.ErrNotFound", err)
+			}
+			return nil
+		},
+	}, {
+		Name: "snapshots dump every table and restore rows by id",
+		Run: func(repos repository.Repositories) error {
+			author, articles, err := seedDays(repos)
+			if err != nil {
+				return err
+			}
+			tag, err := repos.Tags.FindOrCreate("#bitcoin")
+			if err != nil {
+				return err
+			}
+			for _, article := range articles {
+				if err := repos.Tags.SetArticleTags(article.ID, []string{tag.ID}); err != nil {
+					return err
+				}
+			}
+			if err := repos.Articles.IncrementClickCount(articles[0].ID); err != nil {
+				return err
+			}
+			_, err = repos.FileRecords.Create(models.FileRecord{
+				URL:      "https://bucket.s3.region.amazonaws.com/uploads/a.png",
+				Filename: "uploads/a.png",
+			})
+			if err != nil {
+				return err
+			}
+
+			dump := func() ([]repository.SnapshotBatch, error) {
+				var batches []repository.SnapshotBatch
+				err := repos.Snapshots.Dump(2, func(batch repository.SnapshotBatch) error {
+					batches = append(batches, batch)
+					return nil
+				})
+				return batches, err
+			}
+			batches, err := dump()
+			if err != nil {
+				return err
+			}
+
+			var tables []string
+			var dumped repository.SnapshotBatch
+			for _, batch := range batches {
+				if batch.Len() > 2 {
+					return fmt.Errorf("batch of %d rows, want at most 2", batch.Len())
+				}
+				var table string
+				switch {
+				case len(batch.Authors) > 0:
+					table = "authors"
+				case len(batch.Articles) > 0:
+					table = "articles"
+				case len(batch.Tags) > 0:
+					table = "tags"
+				case len(batch.ArticleTags) > 0:
+					table = "articleTags"
+				default:
+					table = "fileRecords"
+				}
+				if len(tables) == 0 || tables[len(tables)-1] != table {
+					tables = append(tables, table)
+				}
+				dumped.Authors = append(dumped.Authors, batch.Authors...)
+				dumped.Articles = append(dumped.Articles, batch.Articles...)
+				dumped.Tags = append(dumped.Tags, batch.Tags...)
+				dumped.ArticleTags = append(dumped.ArticleTags, batch.ArticleTags...)
+				dumped.FileRecords = append(dumped.FileRecords, batch.FileRecords...)
+			}
+			wantTables := []string{"authors", "articles", "tags", "articleTags", "fileRecords"}
+			if !slices.Equal(tables, wantTables) {
+				return fmt.Errorf("dumped tables %v, want %v", tables, wantTables)
+			}
+			if len(dumped.Authors) != 1 || len(dumped.Articles) != 3 || len(dumped.Tags) != 1 ||
+				len(dumped.ArticleTags) != 3 || len(dumped.FileRecords) != 1 {
+				return fmt.Errorf("dumped %d authors, %d articles, %d tags, %d article tags and %d file records",
+					len(dumped.Authors), len(dumped.Articles), len(dumped.Tags), len(dumped.ArticleTags), len(dumped.FileRecords))
+			}
+			for _, article := range dumped.Articles {
+				if article.ID == articles[0].ID && article.ClickCount != 1 {
+					return fmt.Errorf("dumped click count %d, want 1", article.ClickCount)
+				}
+			}
+
+			// Restoring what is already there changes nothing
+			for _, batch := range batches {
+				if err := repos.Snapshots.Restore(batch); err != nil {
+					return fmt.Errorf("restoring the dump again: %w", err)
+				}
+			}
+			again, err := dump()
+			if err != nil {
+				return err
+			}
+			if len(again) != len(batches) {
+				return fmt.Errorf("%d batches after restoring, want %d", len(again), len(batches))
+			}
+
+			restoredAuthor := models.Author{
+				ID:        "5f0c6a3e-8d2b-4c1a-9e7f-3b2a1d0c9e8f",
+				Name:      "Hal",
+				AvatarUrl: "https://example.com/hal.png",
+				CreatedAt: day(2020, time.June, 1, 0),
+				UpdatedAt: day(2020, time.June, 1, 0),
+			}
+			restoredArticle := models.Article{
+				ID:           "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+				AuthorID:     restoredAuthor.ID,
+				Tag:          "#bitcoin",
+				TagIndex:     "a9",
+				Title:        "Restored Article",
+				ImageUrl:     "https://example.com/a9.png",
+				PostedAt:     day(2020, time.June, 2, 10),
+				ReadDuration: "1h 5m",
+				ClickCount:   7,
+				CreatedAt:    day(2020, time.June, 2, 10),
+				UpdatedAt:    day(2020, time.June, 2, 10),
+			}
+			err = repos.Snapshots.Restore(repository.SnapshotBatch{Authors: []models.Author{restoredAuthor}})
+			if err == nil {
+				err = repos.Snapshots.Restore(repository.SnapshotBatch{Articles: []models.Article{restoredArticle}})
+			}
+			if err == nil {
+				err = repos.Snapshots.Restore(repository.SnapshotBatch{ArticleTags: []models.ArticleTag{
+					{ArticleID: restoredArticle.ID, TagID: tag.ID},
+				}})
+			}
+			if err != nil {
+				return err
+			}
+
+			found, err := repos.Articles.FindOne(restoredArticle.ID)
+			if err != nil {
+				return err
+			}
+			if found.AuthorID != restoredAuthor.ID || found.ClickCount != 7 || found.ReadDurationMinutes != 65 ||
+				!found.CreatedAt.Equal(restoredArticle.CreatedAt) {
+				return fmt.Errorf("restored article = %+v", found)
+			}
+			tags, err := repos.Tags.FindByArticle(restoredArticle.ID)
+			if err != nil || len(tags) != 1 || tags[0].ID != tag.ID {
+				return fmt.Errorf("restored article tags = %+v, %v", tags, err)
+			}
+
+			restoredAuthor.ID = "9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+			restoredAuthor.Name = author.Name
+			err = repos.Snapshots.Restore(repository.SnapshotBatch{Authors: []models.Author{restoredAuthor}})
+			if !errors.Is(err, models.ErrConflict) {
+				return fmt.Errorf("restoring another author named %s error = %v, want models.ErrConflict", author.Name, err)
+			}
+			return nil
+		},
+	},
+}
@@ -0,0 +1,62 @@
+package repository
+
+import "time"
+
+type DayCount struct {
+	Date  time.Time
+	Count int64
+}
+
+// FillMissingDays takes day counts ordered from the latest to the
+// oldest day and fills the days in between that have no articles
+// with a zero count
+func FillMissingDays(results []DayCount) []map[string]interface{} {
+	if len(results) == 0 {
+		return []map[string]interface{}{}
+	}
+
+	// Create a map for quick lookup of existing dates and their counts
+	dateCountMap := make(map[string]int64)
+	for _, result := range results {
+		dateStr := result.Date.Format("2006-01-02")
+		dateCountMap[dateStr] = result.Count
+	}
+
+	// Get the first (latest) and last (oldest) dates from results
+	firstDate := results[0].Date             // Latest date
+	lastDate := results[len(results)-1].Date // Oldest date
+
+	// Fill in missing dates between first and last date
+	var dayArticleCounts []map[string]interface{}
+	currentDate := firstDate
+
+	for !currentDate.Before(lastDate) {
+		dateStr := currentDate.Format("2006-01-02")
+
+		// Check if date exists in our results
+		count, exists := dateCountMap[dateStr]
+		if !exists {
+			count = 0 // Set count to 0 for missing dates
+		}
+
+		dayData := map[string]interface{}{
+			"date":  dateStr,
+			"count": count,
+		}
+		dayArticleCounts = append(dayArticleCounts, dayData)
+
+		// Move to previous day (since we're in descending order)
+		currentDate = currentDate.AddDate(0, 0, -1)
+	}
+
+	return dayArticleCounts
+}
+
+// AuthorDayCount is the number of articles an author posted on a
+// calendar day
+type AuthorDayCount struct {
+	Day        time.Time
+	AuthorID   string
+	AuthorName string
+	Count      int64
+}
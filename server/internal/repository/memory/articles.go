@@ -0,0 +1,588 @@
+package memory
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/google/uuid"
+)
+
+type ArticleRepository struct {
+	store *store
+}
+
+// withAuthor returns a copy of the article with its author loaded,
+// the equivalent of Preload("Author")
+func (r *ArticleRepository) withAuthor(article models.Article) models.Article {
+	if author, ok := r.store.authors[article.AuthorID]; ok {
+		article.Author = &author
+	}
+	return article
+}
+
+// filter returns the articles matching every predicate. Callers
+// must hold the store lock
+func (r *ArticleRepository) filter(predicates ...func(article models.Article) bool) []models.Article {
+	articles := []models.Article{}
+
+	for _, article := range r.store.articles {
+		matches := true
+		for _, predicate := range predicates {
+			if !predicate(article) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			article.Author = nil
+			articles = append(articles, article)
+		}
+	}
+	return articles
+}
+
+func sortByTime(articles []models.Article, field func(article models.Article) time.Time, desc bool) {
+	sort.Slice(articles, func(i, j int) bool {
+		a, b := field(articles[i]), field(articles[j])
+		if !a.Equal(b) {
+			if desc {
+				return a.After(b)
+			}
+			return a.Before(b)
+		}
+		return articles[i].ID < articles[j].ID
+	})
+}
+
+func postedAt(article models.Article) time.Time  { return article.PostedAt }
+func updatedAt(article models.Article) time.Time { return article.UpdatedAt }
+
+func paginate(articles []models.Article, limit int, offset int) []models.Article {
+	if offset >= len(articles) {
+		return []models.Article{}
+	}
+	articles = articles[offset:]
+
+	if limit >= 0 && len(articles) > limit {
+		articles = articles[:limit]
+	}
+	return articles
+}
+
+func (r *ArticleRepository) Create(article models.Article) (models.Article, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, ok := r.store.authors[article.AuthorID]; !ok {
+		return article, fmt.Errorf("author %s does not exist", article.AuthorID)
+	}
+
+	now := time.Now()
+	article.ID = uuid.New().String()
+	if article.CreatedAt.IsZero() {
+		article.CreatedAt = now
+	}
+	if article.UpdatedAt.IsZero() {
+		article.UpdatedAt = now
+	}
+	article.Author = nil
+	article.SearchRank = 0
+	article.SearchHighlight = ""
+	article.ReadDurationMinutes = models.ReadDurationMinutes(article.ReadDuration)
+	article.ClickCount = 0
+
+	r.store.articles[article.ID] = article
+
+	return article, nil
+}
+
+func (r *ArticleRepository) FindOne(id string) (models.Article, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	article, ok := r.store.articles[id]
+	if !ok {
+		return models.Article{}, notFound("article")
+	}
+	return article, nil
+}
+
+func (r *ArticleRepository) FindByTitle(title string) (models.Article, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	articles := r.filter(func(article models.Article) bool {
+		return article.Title == title
+	})
+	if len(articles) == 0 {
+		return models.Article{}, notFound("article")
+	}
+	sortByTime(articles, func(article models.Article) time.Time { return article.CreatedAt }, false)
+
+	return articles[0], nil
+}
+
+func (r *ArticleRepository) FindAll(limit float64, cursor string) ([]models.Article, int64, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	var predicates []func(article models.Article) bool
+	if cursor != "" {
+		lastArticle, ok := r.store.articles[cursor]
+		if !ok {
+			return nil, 0, notFound("cursor article")
+		}
+		predicates = append(predicates, func(article models.Article) bool {
+			return article.UpdatedAt.Before(lastArticle.UpdatedAt)
+		})
+	}
+
+	articles := r.filter(predicates...)
+	count := int64(len(articles))
+	sortByTime(articles, updatedAt, true)
+
+	return paginate(articles, int(limit), 0), count, nil
+}
+
+// matchesFilter is the predicate for an ArticleFilter. Callers must
+// hold the store lock
+func (r *ArticleRepository) matchesFilter(filter repository.ArticleFilter) func(article models.Article) bool {
+	authorNames := make(map[string]string)
+	if filter.AuthorName != "" {
+		for _, author := range r.store.authors {
+			authorNames[author.ID] = author.Name
+		}
+	}
+
+	return func(article models.Article) bool {
+		if filter.AuthorID != "" && article.AuthorID != filter.AuthorID {
+			return false
+		}
+		if filter.AuthorName != "" && !strings.EqualFold(authorNames[article.AuthorID], filter.AuthorName) {
+			return false
+		}
+		if filter.Tag != "" && article.Tag != filter.Tag {
+			return false
+		}
+		if filter.TagSlug != "" && !slices.ContainsFunc(r.store.articleTags[article.ID], func(tagID string) bool {
+			return r.store.tags[tagID].Slug == filter.TagSlug
+		}) {
+			return false
+		}
+		if filter.SourceTag != "" && article.SourceTag != filter.SourceTag {
+			return false
+		}
+		if !filter.PostedFrom.IsZero() && article.PostedAt.Before(filter.PostedFrom) {
+			return false
+		}
+		if !filter.PostedTo.IsZero() && article.PostedAt.After(filter.PostedTo) {
+			return false
+		}
+		if filter.MinReadMinutes > 0 && article.ReadDurationMinutes < filter.MinReadMinutes {
+			return false
+		}
+		if filter.MaxReadMinutes > 0 && article.ReadDurationMinutes > filter.MaxReadMinutes {
+			return false
+		}
+		if filter.HasImage != nil && (article.ImageUrl != "") != *filter.HasImage {
+			return false
+		}
+		if filter.HasLink != nil && (article.Href != "") != *filter.HasLink {
+			return false
+		}
+		return true
+	}
+}
+
+// keysetPage pages the articles the way the Postgres keyset query
+// does and loads their authors
+func (r *ArticleRepository) keysetPage(articles []models.Article, sort repository.SortOrder,
+	page repository.PageRequest) repository.ArticlePage {
+	total := int64(len(articles))
+
+	articles = keysetWalk(articles, func(article models.Article) string { return article.ID }, sort, page)
+	for i := range articles {
+		articles[i] = r.withAuthor(articles[i])
+	}
+
+	return repository.NewArticlePage(articles, total, sort, page)
+}
+
+func (r *ArticleRepository) FindPage(filter repository.ArticleFilter, sort repository.SortOrder,
+	page repository.PageRequest) (repository.ArticlePage, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	return r.keysetPage(r.filter(r.matchesFilter(filter)), sort, page), nil
+}
+
+// Stream copies the matching articles before handing them out so
+// handle may call the repository
+func (r *ArticleRepository) Stream(filter repository.ArticleFilter, batchSize int,
+	handle func(articles []models.Article) error) error {
+	r.store.mutex.RLock()
+	articles := r.filter(r.matchesFilter(filter))
+	sortByTime(articles, postedAt, true)
+	for i := range articles {
+		articles[i] = r.withAuthor(articles[i])
+	}
+	r.store.mutex.RUnlock()
+
+	for start := 0; start < len(articles); start += batchSize {
+		end := min(start+batchSize, len(articles))
+		if err := handle(articles[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ArticleRepository) FindAllByPostedAtInAsc(limit int) ([]models.Article, int64, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	articles := r.filter()
+	count := int64(len(articles))
+	sortByTime(articles, postedAt, false)
+
+	return paginate(articles, limit, 0), count, nil
+}
+
+func (r *ArticleRepository) FindAllWithWrongImage(limit float64, cursor string) ([]models.Article, int64, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	wrongFormat := "?auto"
+
+	articles := r.filter(func(article models.Article) bool {
+		return strings.Contains(strings.ToLower(article.ImageUrl), wrongFormat)
+	})
+	count := int64(len(articles))
+	sortByTime(articles, postedAt, true)
+
+	return paginate(articles, int(limit), 0), count, nil
+}
+
+func (r *ArticleRepository) FindAllWithoutImageMetadata(afterID string, limit int) ([]models.Article, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	articles := r.filter(func(article models.Article) bool {
+		return article.ImageBlurHash == "" && article.ImageUrl != "" &&
+			article.ImageMetadataFailedAt == nil && article.ID > afterID
+	})
+	sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+
+	return paginate(articles, limit, 0), nil
+}
+
+func (r *ArticleRepository) MarkImageMetadataFailed(id string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	article, ok := r.store.articles[id]
+	if !ok {
+		return fmt.Errorf("article %w", models.ErrNotFound)
+	}
+	failedAt := time.Now()
+	article.ImageMetadataFailedAt = &failedAt
+	r.store.articles[id] = article
+	return nil
+}
+
+func (r *ArticleRepository) FindCount() (int64, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	return int64(len(r.store.articles)), nil
+}
+
+// Search mirrors the Postgres full-text search without stemming:
+// words match exactly, or by prefix for terms ending with "*"
+func (r *ArticleRepository) Search(options repository.SearchOptions) (repository.ArticlePage, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	query := repository.ParseSearchQuery(options.Query)
+	if len(query) == 0 && !options.Fuzzy {
+		return repository.ArticlePage{Articles: []models.Article{}}, nil
+	}
+
+	articles := []models.Article{}
+	for _, article := range r.filter() {
+		var authorName string
+		if author, ok := r.store.authors[article.AuthorID]; ok {
+			authorName = author.Name
+		}
+
+		var rank float64
+		matches := false
+		if len(query) > 0 {
+			rank, matches = searchRank(query, article, authorName)
+		}
+		if options.Fuzzy {
+			titleSimilarity := similarity(article.Title, options.Query)
+			if titleSimilarity >= options.FuzzyThreshold {
+				matches = true
+			}
+			rank += titleSimilarity
+		}
+		if !matches {
+			continue
+		}
+
+		article.SearchRank = rank
+		article.SearchHighlight = html.EscapeString(article.Title)
+		if len(query) > 0 {
+			article.SearchHighlight = searchHighlight(query, strings.TrimSpace(article.Title+" "+article.Body))
+		}
+		articles = append(articles, article)
+	}
+
+	return r.keysetPage(articles, repository.SortRelevance, options.Page), nil
+}
+
+func (r *ArticleRepository) DidYouMean(searchQuery string, threshold float64) (string, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	articles := r.filter()
+	sortByTime(articles, postedAt, true)
+
+	var title string
+	var best float64
+	for _, article := range articles {
+		score := wordSimilarity(searchQuery, article.Title)
+		if score >= threshold && score > best {
+			title, best = article.Title, score
+		}
+	}
+
+	return title, nil
+}
+
+func (r *ArticleRepository) SearchByTagIndex(searchQuery string) ([]models.Article, int64, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	articles := r.filter(func(article models.Article) bool {
+		return article.TagIndex == searchQuery
+	})
+	sortByTime(articles, postedAt, true)
+
+	for i := range articles {
+		articles[i] = r.withAuthor(articles[i])
+	}
+
+	return articles, int64(len(articles)), nil
+}
+
+func (r *ArticleRepository) FindByTagIndex(tagIndex string) (models.Article, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	articles := r.filter(func(article models.Article) bool {
+		return article.TagIndex == tagIndex
+	})
+	if len(articles) == 0 {
+		return models.Article{}, notFound("article")
+	}
+	sortByTime(articles, postedAt, true)
+
+	return r.withAuthor(articles[0]), nil
+}
+
+func (r *ArticleRepository) FindNeighbours(article models.Article) (repository.ArticleNeighbours, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	var neighbours repository.ArticleNeighbours
+
+	// Articles are ordered on (postedAt, id), as in the keyset listings
+	compare := func(other models.Article) int {
+		if result := other.PostedAt.Compare(article.PostedAt); result != 0 {
+			return result
+		}
+		return strings.Compare(other.ID, article.ID)
+	}
+
+	articles := r.filter()
+	sortByTime(articles, postedAt, false)
+	for _, other := range articles {
+		if compare(other) < 0 {
+			previous := r.withAuthor(other)
+			neighbours.Previous = &previous
+		}
+		if compare(other) > 0 {
+			next := r.withAuthor(other)
+			neighbours.Next = &next
+			break
+		}
+	}
+
+	return neighbours, nil
+}
+
+func (r *ArticleRepository) IncrementClickCount(id string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	article, ok := r.store.articles[id]
+	if !ok {
+		return notFound("article")
+	}
+	article.ClickCount++
+	r.store.articles[id] = article
+
+	return nil
+}
+
+func (r *ArticleRepository) FindByPostedAt(date time.Time, location *time.Location) ([]models.Article, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	date = asWritten(date)
+
+	articles := r.filter(func(article models.Article) bool {
+		return dayIn(article.PostedAt, location).Equal(date)
+	})
+	sortByTime(articles, postedAt, true)
+
+	for i := range articles {
+		articles[i] = r.withAuthor(articles[i])
+	}
+
+	return articles, nil
+}
+
+// dayIn returns the calendar day of t in location as midnight UTC,
+// the equivalent of ("postedAt" AT TIME ZONE location)::date
+func dayIn(t time.Time, location *time.Location) time.Time {
+	return asWritten(t.In(location))
+}
+
+// asWritten returns the calendar day of t in its own zone as midnight
+// UTC
+func asWritten(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (r *ArticleRepository) FindArticleCountPerDay(limit int, dateCursor time.Time,
+	location *time.Location) ([]map[string]interface{}, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	counts := make(map[time.Time]int64)
+	for _, article := range r.store.articles {
+		if !dateCursor.IsZero() && !dayIn(article.PostedAt, location).Before(asWritten(dateCursor)) {
+			continue
+		}
+		counts[dayIn(article.PostedAt, location)]++
+	}
+
+	dayCounts := []repository.DayCount{}
+	for date, count := range counts {
+		dayCounts = append(dayCounts, repository.DayCount{Date: date, Count: count})
+	}
+	sort.Slice(dayCounts, func(i, j int) bool {
+		return dayCounts[i].Date.After(dayCounts[j].Date)
+	})
+	if len(dayCounts) > limit {
+		dayCounts = dayCounts[:limit]
+	}
+
+	return repository.FillMissingDays(dayCounts), nil
+}
+
+func (r *ArticleRepository) CountDistinctDays(count *int64, location *time.Location) error {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	days := make(map[time.Time]bool)
+	for _, article := range r.store.articles {
+		days[dayIn(article.PostedAt, location)] = true
+	}
+	*count = int64(len(days))
+
+	return nil
+}
+
+// RebuildDayCounts has nothing to rebuild, days are counted on read
+func (r *ArticleRepository) RebuildDayCounts(zones []string) error {
+	return nil
+}
+
+func (r *ArticleRepository) FindAuthorDayCounts(location *time.Location) ([]repository.AuthorDayCount, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	type dayAuthor struct {
+		day      time.Time
+		authorID string
+	}
+	counts := make(map[dayAuthor]int64)
+	for _, article := range r.store.articles {
+		counts[dayAuthor{dayIn(article.PostedAt, location), article.AuthorID}]++
+	}
+
+	dayCounts := make([]repository.AuthorDayCount, 0, len(counts))
+	for key, count := range counts {
+		dayCounts = append(dayCounts, repository.AuthorDayCount{
+			Day:        key.day,
+			AuthorID:   key.authorID,
+			AuthorName: r.store.authors[key.authorID].Name,
+			Count:      count,
+		})
+	}
+	sort.Slice(dayCounts, func(i, j int) bool {
+		if !dayCounts[i].Day.Equal(dayCounts[j].Day) {
+			return dayCounts[i].Day.Before(dayCounts[j].Day)
+		}
+		return dayCounts[i].AuthorID < dayCounts[j].AuthorID
+	})
+
+	return dayCounts, nil
+}
+
+// Update saves the article, inserting it when it doesn't exist
+// yet like gorm's Save does
+func (r *ArticleRepository) Update(article models.Article) (models.Article, error) {
+	r.store.mutex.Lock()
+
+	if article.ID == "" {
+		article.ID = uuid.New().String()
+	}
+	if article.CreatedAt.IsZero() {
+		article.CreatedAt = time.Now()
+	}
+	article.UpdatedAt = time.Now()
+	article.Author = nil
+	article.SearchRank = 0
+	article.SearchHighlight = ""
+	article.ReadDurationMinutes = models.ReadDurationMinutes(article.ReadDuration)
+	article.ClickCount = r.store.articles[article.ID].ClickCount
+	r.store.articles[article.ID] = article
+
+	r.store.mutex.Unlock()
+
+	return r.FindOne(article.ID)
+}
+
+func (r *ArticleRepository) Delete(id string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, ok := r.store.articles[id]; !ok {
+		return notFound("article")
+	}
+	delete(r.store.articles, id)
+	delete(r.store.articleTags, id)
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/google/uuid"
+)
+
+type AuthorRepository struct {
+	store *store
+}
+
+func (r *AuthorRepository) Create(author models.Author) (models.Author, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for _, savedAuthor := range r.store.authors {
+		if savedAuthor.Name == author.Name {
+			return author, conflict("author")
+		}
+	}
+
+	now := time.Now()
+	author.ID = uuid.New().String()
+	if author.CreatedAt.IsZero() {
+		author.CreatedAt = now
+	}
+	if author.UpdatedAt.IsZero() {
+		author.UpdatedAt = now
+	}
+	author.Article = nil
+	author.ArticleCount = 0
+	author.LatestPostedAt = nil
+
+	r.store.authors[author.ID] = author
+
+	return author, nil
+}
+
+func (r *AuthorRepository) FindOne(id string) (models.Author, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	author, ok := r.store.authors[id]
+	if !ok {
+		return models.Author{}, notFound("author")
+	}
+	return author, nil
+}
+
+func (r *AuthorRepository) findFirst(match func(author models.Author) bool) (models.Author, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	for _, author := range r.store.authors {
+		if match(author) {
+			return author, nil
+		}
+	}
+	return models.Author{}, notFound("author")
+}
+
+func (r *AuthorRepository) FindByName(name string) (models.Author, error) {
+	return r.findFirst(func(author models.Author) bool {
+		return author.Name == name
+	})
+}
+
+func (r *AuthorRepository) FindByPage(pageURL string) (models.Author, error) {
+	return r.findFirst(func(author models.Author) bool {
+		return author.PageUrl == pageURL
+	})
+}
+
+func (r *AuthorRepository) FindAll(limit float64, cursor string) ([]models.Author, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	var cursorTime time.Time
+	if cursor != "" {
+		lastAuthor, ok := r.store.authors[cursor]
+		if !ok {
+			return nil, notFound("cursor author")
+		}
+		cursorTime = lastAuthor.UpdatedAt
+	}
+
+	authors := []models.Author{}
+	for _, author := range r.store.authors {
+		if cursor != "" && !author.UpdatedAt.Before(cursorTime) {
+			continue
+		}
+		authors = append(authors, author)
+	}
+
+	sort.Slice(authors, func(i, j int) bool {
+		if !authors[i].UpdatedAt.Equal(authors[j].UpdatedAt) {
+			return authors[i].UpdatedAt.After(authors[j].UpdatedAt)
+		}
+		return authors[i].ID < authors[j].ID
+	})
+
+	if len(authors) > int(limit) {
+		authors = authors[:int(limit)]
+	}
+
+	return authors, nil
+}
+
+func (r *AuthorRepository) FindPage(filter repository.AuthorFilter, sort repository.AuthorSortOrder,
+	page repository.PageRequest) (repository.AuthorPage, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	authors := []models.Author{}
+	for _, author := range r.store.authors {
+		if filter.Search != "" && !strings.Contains(strings.ToLower(author.Name), strings.ToLower(filter.Search)) {
+			continue
+		}
+		authors = append(authors, author)
+	}
+	total := int64(len(authors))
+
+	for i := range authors {
+		for _, article := range r.store.articles {
+			if article.AuthorID != authors[i].ID {
+				continue
+			}
+			authors[i].ArticleCount++
+			if authors[i].LatestPostedAt == nil || article.PostedAt.After(*authors[i].LatestPostedAt) {
+				postedAt := article.PostedAt
+				authors[i].LatestPostedAt = &postedAt
+			}
+		}
+	}
+
+	authors = keysetWalk(authors, func(author models.Author) string { return author.ID }, sort, page)
+
+	return repository.NewAuthorPage(authors, total, sort, page), nil
+}
+
+func (r *AuthorRepository) FindStats(id string) (repository.AuthorStats, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	stats := repository.AuthorStats{Tags: []repository.TagCount{}}
+	tagCounts := make(map[string]int64)
+	var readMinutes, readCount int
+
+	for _, article := range r.store.articles {
+		if article.AuthorID != id {
+			continue
+		}
+		stats.ArticleCount++
+		if stats.FirstPostedAt == nil || article.PostedAt.Before(*stats.FirstPostedAt) {
+			postedAt := article.PostedAt
+			stats.FirstPostedAt = &postedAt
+		}
+		if stats.LastPostedAt == nil || article.PostedAt.After(*stats.LastPostedAt) {
+			postedAt := article.PostedAt
+			stats.LastPostedAt = &postedAt
+		}
+		if article.ReadDurationMinutes > 0 {
+			readMinutes += article.ReadDurationMinutes
+			readCount++
+		}
+		tagCounts[article.Tag]++
+	}
+
+	if readCount > 0 {
+		stats.AverageReadMinutes = float64(readMinutes) / float64(readCount)
+	}
+	for tag, count := range tagCounts {
+		stats.Tags = append(stats.Tags, repository.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(stats.Tags, func(i, j int) bool {
+		if stats.Tags[i].Count != stats.Tags[j].Count {
+			return stats.Tags[i].Count > stats.Tags[j].Count
+		}
+		return stats.Tags[i].Tag < stats.Tags[j].Tag
+	})
+
+	return stats, nil
+}
+
+func (r *AuthorRepository) Update(author models.Author) (models.Author, error) {
+	r.store.mutex.Lock()
+
+	if author.ID == "" {
+		author.ID = uuid.New().String()
+	}
+	for _, savedAuthor := range r.store.authors {
+		if savedAuthor.ID != author.ID && savedAuthor.Name == author.Name {
+			r.store.mutex.Unlock()
+			return author, conflict("author")
+		}
+	}
+	if author.CreatedAt.IsZero() {
+		author.CreatedAt = time.Now()
+	}
+	author.UpdatedAt = time.Now()
+	author.Article = nil
+	author.ArticleCount = 0
+	author.LatestPostedAt = nil
+	r.store.authors[author.ID] = author
+
+	r.store.mutex.Unlock()
+
+	return r.FindOne(author.ID)
+}
+
+// Delete removes the author along with their articles, mirroring
+// the ON DELETE CASCADE foreign key in Postgres
+func (r *AuthorRepository) Delete(id string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, ok := r.store.authors[id]; !ok {
+		return notFound("author")
+	}
+	delete(r.store.authors, id)
+	for articleID, article := range r.store.articles {
+		if article.AuthorID == id {
+			delete(r.store.articles, articleID)
+			delete(r.store.articleTags, articleID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/google/uuid"
+)
+
+type FileRecordRepository struct {
+	store *store
+}
+
+func (r *FileRecordRepository) Create(fileRecord models.FileRecord) (models.FileRecord, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for _, savedFileRecord := range r.store.fileRecords {
+		if savedFileRecord.Filename == fileRecord.Filename {
+			return fileRecord, conflict("file record")
+		}
+	}
+
+	now := time.Now()
+	fileRecord.ID = uuid.New().String()
+	if fileRecord.CreatedAt.IsZero() {
+		fileRecord.CreatedAt = now
+	}
+	if fileRecord.UpdatedAt.IsZero() {
+		fileRecord.UpdatedAt = now
+	}
+	r.store.fileRecords[fileRecord.ID] = fileRecord
+
+	return fileRecord, nil
+}
+
+func (r *FileRecordRepository) FindByFilename(filename string) (models.FileRecord, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	for _, fileRecord := range r.store.fileRecords {
+		if fileRecord.Filename == filename {
+			return fileRecord, nil
+		}
+	}
+	return models.FileRecord{}, notFound("file record")
+}
+
+func (r *FileRecordRepository) Delete(id string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, ok := r.store.fileRecords[id]; !ok {
+		return notFound("file record")
+	}
+	delete(r.store.fileRecords, id)
+
+	return nil
+}
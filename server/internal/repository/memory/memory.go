@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// store holds every table so repositories can enforce the same
+// relations Postgres does (unique author names, article -> author
+// and article_tags foreign keys with cascading deletes)
+type store struct {
+	articles       map[string]models.Article
+	authors        map[string]models.Author
+	tags           map[string]models.Tag
+	articleTags    map[string][]string
+	fileRecords    map[string]models.FileRecord
+	pendingUploads map[string]models.PendingUpload
+	mutex          sync.RWMutex
+}
+
+func newStore() *store {
+	return &store{
+		articles:       make(map[string]models.Article),
+		authors:        make(map[string]models.Author),
+		tags:           make(map[string]models.Tag),
+		articleTags:    make(map[string][]string),
+		fileRecords:    make(map[string]models.FileRecord),
+		pendingUploads: make(map[string]models.PendingUpload),
+	}
+}
+
+// NewRepositories returns in-memory repositories sharing one store.
+// They are meant for tests and local development without Postgres
+func NewRepositories() repository.Repositories {
+	s := newStore()
+
+	return repository.Repositories{
+		Articles:       &ArticleRepository{store: s},
+		Authors:        &AuthorRepository{store: s},
+		Tags:           &TagRepository{store: s},
+		FileRecords:    &FileRecordRepository{store: s},
+		PendingUploads: &PendingUploadRepository{store: s},
+		Snapshots:      &SnapshotRepository{store: s},
+	}
+}
+
+var (
+	_ repository.ArticleRepository       = (*ArticleRepository)(nil)
+	_ repository.AuthorRepository        = (*AuthorRepository)(nil)
+	_ repository.TagRepository           = (*TagRepository)(nil)
+	_ repository.FileRecordRepository    = (*FileRecordRepository)(nil)
+	_ repository.PendingUploadRepository = (*PendingUploadRepository)(nil)
+	_ repository.SnapshotRepository      = (*SnapshotRepository)(nil)
+)
+
+// notFound and conflict wrap the model sentinel errors the same way
+// the Postgres repositories do
+func notFound(entity string) error {
+	return fmt.Errorf("%s %w", entity, models.ErrNotFound)
+}
+
+func conflict(entity string) error {
+	return fmt.Errorf("%s %w", entity, models.ErrConflict)
+}
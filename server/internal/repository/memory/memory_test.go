@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/contract"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/memory"
+)
+
+func TestContract(t *testing.T) {
+	contract.Run(t, func() (repository.Repositories, error) {
+		return memory.NewRepositories(), nil
+	})
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// keysetWalk orders items on (sort key, id) the way the page walks,
+// drops those up to the cursor and keeps one more than the limit,
+// which is what the Postgres keyset queries fetch
+func keysetWalk[T any](items []T, id func(item T) string, sort repository.Sort[T], page repository.PageRequest) []T {
+	desc := sort.Desc
+	if page.Cursor != nil && page.Cursor.Backward {
+		desc = !desc
+	}
+	compare := func(aKey, aID, bKey, bID string) int {
+		if result := sort.CompareKeys(aKey, bKey); result != 0 {
+			return result
+		}
+		return strings.Compare(aID, bID)
+	}
+
+	slices.SortFunc(items, func(a, b T) int {
+		result := compare(sort.Key(a), id(a), sort.Key(b), id(b))
+		if desc {
+			return -result
+		}
+		return result
+	})
+
+	if page.Cursor != nil {
+		var afterCursor []T
+		for _, item := range items {
+			result := compare(sort.Key(item), id(item), page.Cursor.Key, page.Cursor.ID)
+			if (desc && result < 0) || (!desc && result > 0) {
+				afterCursor = append(afterCursor, item)
+			}
+		}
+		items = afterCursor
+	}
+
+	if len(items) > page.Limit+1 {
+		items = items[:page.Limit+1]
+	}
+	return items
+}
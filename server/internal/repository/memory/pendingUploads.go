@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+)
+
+type PendingUploadRepository struct {
+	store *store
+}
+
+func (r *PendingUploadRepository) Create(pendingUpload models.PendingUpload) (models.PendingUpload, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, exists := r.store.pendingUploads[pendingUpload.Key]; exists {
+		return pendingUpload, conflict("pending upload")
+	}
+	if pendingUpload.CreatedAt.IsZero() {
+		pendingUpload.CreatedAt = time.Now()
+	}
+	r.store.pendingUploads[pendingUpload.Key] = pendingUpload
+
+	return pendingUpload, nil
+}
+
+func (r *PendingUploadRepository) FindByKey(key string) (models.PendingUpload, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	pendingUpload, ok := r.store.pendingUploads[key]
+	if !ok {
+		return pendingUpload, notFound("pending upload")
+	}
+	return pendingUpload, nil
+}
+
+func (r *PendingUploadRepository) FindExpired(before time.Time, limit int) ([]models.PendingUpload, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	var pendingUploads []models.PendingUpload
+	for _, pendingUpload := range r.store.pendingUploads {
+		if pendingUpload.ExpiresAt.Before(before) {
+			pendingUploads = append(pendingUploads, pendingUpload)
+		}
+	}
+	sort.Slice(pendingUploads, func(i, j int) bool {
+		return pendingUploads[i].ExpiresAt.Before(pendingUploads[j].ExpiresAt)
+	})
+
+	return pendingUploads[:min(limit, len(pendingUploads))], nil
+}
+
+func (r *PendingUploadRepository) Delete(key string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, ok := r.store.pendingUploads[key]; !ok {
+		return notFound("pending upload")
+	}
+	delete(r.store.pendingUploads, key)
+
+	return nil
+}
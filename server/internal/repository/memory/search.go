@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// Field weights follow ts_rank's defaults for the A, B and D labels
+// the Postgres search vector gives title, tag/author and body
+type searchField struct {
+	words  []string
+	weight float64
+}
+
+func matchesWord(word, want string, prefix bool) bool {
+	if prefix {
+		return strings.HasPrefix(word, want)
+	}
+	return word == want
+}
+
+// matchesTerm reports whether the words contain the term, phrase
+// words having to appear next to each other
+func matchesTerm(words []string, term repository.SearchTerm) bool {
+	for start := 0; start+len(term.Words) <= len(words); start++ {
+		matches := true
+		for i, want := range term.Words {
+			prefix := term.Prefix && i == len(term.Words)-1
+			if !matchesWord(words[start+i], want, prefix) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// searchRank returns the summed weight of the fields each term
+// matches, and false when a term matches none of them
+func searchRank(query repository.SearchQuery, article models.Article, authorName string) (float64, bool) {
+	fields := []searchField{
+		{words: repository.SearchWords(article.Title), weight: 1.0},
+		{words: repository.SearchWords(article.Tag), weight: 0.4},
+		{words: repository.SearchWords(authorName), weight: 0.4},
+		{words: repository.SearchWords(article.Body), weight: 0.1},
+	}
+
+	var rank float64
+	for _, term := range query {
+		var termRank float64
+		for _, field := range fields {
+			if matchesTerm(field.words, term) {
+				termRank += field.weight
+			}
+		}
+		if termRank == 0 {
+			return 0, false
+		}
+		rank += termRank
+	}
+
+	return rank, true
+}
+
+// searchHighlight wraps the words of text that match a query word in
+// <mark>, like ts_headline does. Everything else is HTML escaped the
+// way the Postgres highlight is
+func searchHighlight(query repository.SearchQuery, text string) string {
+	isMatch := func(word string) bool {
+		word = strings.ToLower(word)
+		for _, term := range query {
+			for i, want := range term.Words {
+				prefix := term.Prefix && i == len(term.Words)-1
+				if matchesWord(word, want, prefix) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var highlight strings.Builder
+	var word strings.Builder
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		if isMatch(word.String()) {
+			highlight.WriteString("<mark>" + word.String() + "</mark>")
+		} else {
+			highlight.WriteString(word.String())
+		}
+		word.Reset()
+	}
+
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word.WriteRune(r)
+			continue
+		}
+		flush()
+		highlight.WriteString(html.EscapeString(string(r)))
+	}
+	flush()
+
+	return highlight.String()
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+type SnapshotRepository struct {
+	store *store
+}
+
+// Dump copies the tables under the read lock, so the snapshot is
+// consistent, and hands the copies out in id order once released
+func (r *SnapshotRepository) Dump(batchSize int, handle func(batch repository.SnapshotBatch) error) error {
+	r.store.mutex.RLock()
+
+	authors := sortedByID(r.store.authors, func(author models.Author) string { return author.ID })
+	articles := sortedByID(r.store.articles, func(article models.Article) string { return article.ID })
+	tags := sortedByID(r.store.tags, func(tag models.Tag) string { return tag.ID })
+	fileRecords := sortedByID(r.store.fileRecords, func(fileRecord models.FileRecord) string { return fileRecord.ID })
+
+	var articleTags []models.ArticleTag
+	for articleID, tagIDs := range r.store.articleTags {
+		for _, tagID := range tagIDs {
+			articleTags = append(articleTags, models.ArticleTag{ArticleID: articleID, TagID: tagID})
+		}
+	}
+	sort.Slice(articleTags, func(i, j int) bool {
+		if articleTags[i].ArticleID != articleTags[j].ArticleID {
+			return articleTags[i].ArticleID < articleTags[j].ArticleID
+		}
+		return articleTags[i].TagID < articleTags[j].TagID
+	})
+
+	r.store.mutex.RUnlock()
+
+	for i := range articles {
+		articles[i].Author = nil
+	}
+
+	err := dumpBatches(authors, batchSize, func(rows []models.Author) error {
+		return handle(repository.SnapshotBatch{Authors: rows})
+	})
+	if err == nil {
+		err = dumpBatches(articles, batchSize, func(rows []models.Article) error {
+			return handle(repository.SnapshotBatch{Articles: rows})
+		})
+	}
+	if err == nil {
+		err = dumpBatches(tags, batchSize, func(rows []models.Tag) error {
+			return handle(repository.SnapshotBatch{Tags: rows})
+		})
+	}
+	if err == nil {
+		err = dumpBatches(articleTags, batchSize, func(rows []models.ArticleTag) error {
+			return handle(repository.SnapshotBatch{ArticleTags: rows})
+		})
+	}
+	if err == nil {
+		err = dumpBatches(fileRecords, batchSize, func(rows []models.FileRecord) error {
+			return handle(repository.SnapshotBatch{FileRecords: rows})
+		})
+	}
+	return err
+}
+
+func sortedByID[T any](rows map[string]T, id func(T) string) []T {
+	sorted := make([]T, 0, len(rows))
+	for _, row := range rows {
+		sorted = append(sorted, row)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return id(sorted[i]) < id(sorted[j])
+	})
+	return sorted
+}
+
+func dumpBatches[T any](rows []T, batchSize int, handle func(rows []T) error) error {
+	for start := 0; start < len(rows); start += batchSize {
+		if err := handle(rows[start:min(start+batchSize, len(rows))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore checks the whole batch against the unique columns and
+// foreign keys before writing, a failing batch changes nothing like
+// the Postgres transaction
+func (r *SnapshotRepository) Restore(batch repository.SnapshotBatch) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for _, author := range batch.Authors {
+		for _, savedAuthor := range r.store.authors {
+			if savedAuthor.ID != author.ID && savedAuthor.Name == author.Name {
+				return conflict("author")
+			}
+		}
+	}
+	for _, article := range batch.Articles {
+		if _, ok := r.store.authors[article.AuthorID]; !ok {
+			return fmt.Errorf("author %s does not exist", article.AuthorID)
+		}
+	}
+	for _, tag := range batch.Tags {
+		for _, savedTag := range r.store.tags {
+			if savedTag.ID != tag.ID && savedTag.Slug == tag.Slug {
+				return conflict("tag")
+			}
+		}
+	}
+	for _, articleTag := range batch.ArticleTags {
+		if _, ok := r.store.articles[articleTag.ArticleID]; !ok {
+			return fmt.Errorf("article %s does not exist", articleTag.ArticleID)
+		}
+		if _, ok := r.store.tags[articleTag.TagID]; !ok {
+			return fmt.Errorf("tag %s does not exist", articleTag.TagID)
+		}
+	}
+	for _, fileRecord := range batch.FileRecords {
+		for _, savedFileRecord := range r.store.fileRecords {
+			if savedFileRecord.ID != fileRecord.ID && savedFileRecord.Filename == fileRecord.Filename {
+				return conflict("file record")
+			}
+		}
+	}
+
+	for _, author := range batch.Authors {
+		author.Article = nil
+		author.ArticleCount = 0
+		author.LatestPostedAt = nil
+		r.store.authors[author.ID] = author
+	}
+	for _, article := range batch.Articles {
+		article.Author = nil
+		article.SearchRank = 0
+		article.SearchHighlight = ""
+		article.ReadDurationMinutes = models.ReadDurationMinutes(article.ReadDuration)
+		r.store.articles[article.ID] = article
+	}
+	for _, tag := range batch.Tags {
+		tag.ArticleCount = 0
+		r.store.tags[tag.ID] = tag
+	}
+	for _, articleTag := range batch.ArticleTags {
+		tagIDs := r.store.articleTags[articleTag.ArticleID]
+		if !slices.Contains(tagIDs, articleTag.TagID) {
+			r.store.articleTags[articleTag.ArticleID] = append(tagIDs, articleTag.TagID)
+		}
+	}
+	for _, fileRecord := range batch.FileRecords {
+		r.store.fileRecords[fileRecord.ID] = fileRecord
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/google/uuid"
+)
+
+type TagRepository struct {
+	store *store
+}
+
+// findBySlug matches the slug or a spelling in the aliases, preferring
+// the tag owning the slug. Callers must hold the store lock
+func (r *TagRepository) findBySlug(slug, spelling string) (models.Tag, error) {
+	var aliased *models.Tag
+	for _, tag := range r.store.tags {
+		if tag.Slug == slug {
+			return tag, nil
+		}
+		if aliased == nil && slices.Contains(tag.Aliases, spelling) {
+			aliased = &tag
+		}
+	}
+	if aliased != nil {
+		return *aliased, nil
+	}
+	return models.Tag{}, notFound("tag")
+}
+
+func (r *TagRepository) FindOrCreate(name string) (models.Tag, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	slug := models.TagSlug(name)
+	if slug == "" {
+		return models.Tag{}, fmt.Errorf("tag %q has no letters or digits", name)
+	}
+	spelling := models.TagName(name)
+
+	if tag, err := r.findBySlug(slug, spelling); err == nil {
+		// Record the spelling like the Postgres repository does
+		if spelling != tag.Name && !slices.Contains(tag.Aliases, spelling) {
+			tag.Aliases = append(slices.Clone(tag.Aliases), spelling)
+			tag.UpdatedAt = time.Now()
+			r.store.tags[tag.ID] = tag
+		}
+		return tag, nil
+	}
+
+	now := time.Now()
+	tag := models.Tag{
+		ID:        uuid.New().String(),
+		Slug:      slug,
+		Name:      spelling,
+		Aliases:   []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.store.tags[tag.ID] = tag
+
+	return tag, nil
+}
+
+func (r *TagRepository) FindBySlug(slug string) (models.Tag, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	return r.findBySlug(models.TagSlug(slug), models.TagName(slug))
+}
+
+func (r *TagRepository) FindPage(filter repository.TagFilter, sort repository.TagSortOrder,
+	page repository.PageRequest) (repository.TagPage, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	search := strings.ToLower(filter.Search)
+
+	tags := []models.Tag{}
+	for _, tag := range r.store.tags {
+		if search != "" && !strings.Contains(tag.Slug, search) && !strings.Contains(strings.ToLower(tag.Name), search) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	total := int64(len(tags))
+
+	for i := range tags {
+		for _, tagIDs := range r.store.articleTags {
+			if slices.Contains(tagIDs, tags[i].ID) {
+				tags[i].ArticleCount++
+			}
+		}
+	}
+
+	tags = keysetWalk(tags, func(tag models.Tag) string { return tag.ID }, sort, page)
+
+	return repository.NewTagPage(tags, total, sort, page), nil
+}
+
+func (r *TagRepository) FindByArticle(articleID string) ([]models.Tag, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	tags := []models.Tag{}
+	for _, tagID := range r.store.articleTags[articleID] {
+		tags = append(tags, r.store.tags[tagID])
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Slug < tags[j].Slug
+	})
+
+	return tags, nil
+}
+
+// SetArticleTags replaces the tags of the article, enforcing the
+// article_tags foreign keys
+func (r *TagRepository) SetArticleTags(articleID string, tagIDs []string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, ok := r.store.articles[articleID]; !ok {
+		return fmt.Errorf("article %s does not exist", articleID)
+	}
+
+	var articleTagIDs []string
+	for _, tagID := range tagIDs {
+		if _, ok := r.store.tags[tagID]; !ok {
+			return fmt.Errorf("tag %s does not exist", tagID)
+		}
+		if !slices.Contains(articleTagIDs, tagID) {
+			articleTagIDs = append(articleTagIDs, tagID)
+		}
+	}
+
+	if len(articleTagIDs) == 0 {
+		delete(r.store.articleTags, articleID)
+		return nil
+	}
+	r.store.articleTags[articleID] = articleTagIDs
+
+	return nil
+}
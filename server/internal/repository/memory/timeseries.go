@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+func (r *ArticleRepository) FindTimeseries(options repository.TimeseriesOptions) ([]repository.TimeseriesSeries, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	if options.GroupBy != "" && options.GroupBy != repository.GroupByTag && options.GroupBy != repository.GroupByAuthor {
+		return nil, fmt.Errorf("unsupported timeseries grouping %q", options.GroupBy)
+	}
+
+	buckets := repository.Buckets(options.From, options.To, options.Interval)
+	if len(buckets) == 0 {
+		return []repository.TimeseriesSeries{}, nil
+	}
+	end := repository.NextBucket(buckets[len(buckets)-1], options.Interval)
+
+	type group struct {
+		series repository.TimeseriesSeries
+		counts map[time.Time]int64
+	}
+	groups := make(map[string]*group)
+	add := func(key, label string, bucket time.Time) {
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				series: repository.TimeseriesSeries{Key: key, Label: label},
+				counts: make(map[time.Time]int64),
+			}
+			groups[key] = g
+		}
+		g.series.Total++
+		g.counts[bucket]++
+	}
+
+	if options.GroupBy == "" {
+		groups["all"] = &group{
+			series: repository.TimeseriesSeries{Key: "all", Label: "All articles"},
+			counts: make(map[time.Time]int64),
+		}
+	}
+
+	for _, article := range r.store.articles {
+		day := dayIn(article.PostedAt, options.Location)
+		if day.Before(buckets[0]) || !day.Before(end) {
+			continue
+		}
+		bucket := repository.TruncateDate(day, options.Interval)
+
+		switch options.GroupBy {
+		case repository.GroupByAuthor:
+			author, ok := r.store.authors[article.AuthorID]
+			if ok {
+				add(author.ID, author.Name, bucket)
+			}
+		case repository.GroupByTag:
+			for _, tagID := range r.store.articleTags[article.ID] {
+				tag := r.store.tags[tagID]
+				add(tag.Slug, tag.Name, bucket)
+			}
+		default:
+			add("all", "All articles", bucket)
+		}
+	}
+
+	ordered := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		ordered = append(ordered, g)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].series.Total != ordered[j].series.Total {
+			return ordered[i].series.Total > ordered[j].series.Total
+		}
+		return ordered[i].series.Key < ordered[j].series.Key
+	})
+	if options.GroupBy != "" && len(ordered) > options.GroupLimit {
+		ordered = ordered[:options.GroupLimit]
+	}
+
+	series := make([]repository.TimeseriesSeries, 0, len(ordered))
+	for _, g := range ordered {
+		for _, bucket := range buckets {
+			g.series.Points = append(g.series.Points, repository.TimeseriesPoint{
+				Bucket: bucket.Format(time.DateOnly),
+				Count:  g.counts[bucket],
+			})
+		}
+		series = append(series, g.series)
+	}
+
+	return series, nil
+}
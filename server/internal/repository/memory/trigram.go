@@ -0,0 +1,50 @@
+package memory
+
+import "github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+
+// trigrams returns the set of trigrams pg_trgm would extract: every
+// word is lowercased and padded with two spaces in front, one behind
+func trigrams(text string) map[string]bool {
+	set := make(map[string]bool)
+
+	for _, word := range repository.SearchWords(text) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+func sharedTrigrams(a, b map[string]bool) int {
+	shared := 0
+	for trigram := range a {
+		if b[trigram] {
+			shared++
+		}
+	}
+	return shared
+}
+
+// similarity mirrors pg_trgm's similarity(): shared trigrams over
+// the trigrams of both strings
+func similarity(a, b string) float64 {
+	aTrigrams, bTrigrams := trigrams(a), trigrams(b)
+	shared := sharedTrigrams(aTrigrams, bTrigrams)
+
+	total := len(aTrigrams) + len(bTrigrams) - shared
+	if total == 0 {
+		return 0
+	}
+	return float64(shared) / float64(total)
+}
+
+// wordSimilarity approximates pg_trgm's word_similarity(): the share
+// of the query's trigrams found in the text
+func wordSimilarity(query, text string) float64 {
+	queryTrigrams := trigrams(query)
+	if len(queryTrigrams) == 0 {
+		return 0
+	}
+	return float64(sharedTrigrams(queryTrigrams, trigrams(text))) / float64(len(queryTrigrams))
+}
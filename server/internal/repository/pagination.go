@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Sort describes a keyset ordering of T on (Key, id). Key renders
+// the sort value of an item for cursors, ParseKey turns it back into
+// a value backends can compare against
+type Sort[T any] struct {
+	Name     string
+	Desc     bool
+	Key      func(item T) string
+	ParseKey func(key string) (interface{}, error)
+}
+
+// SortOrder orders article listings
+type SortOrder = Sort[models.Article]
+
+// CompareKeys orders two keys of the sort the way the database does
+func (s Sort[T]) CompareKeys(a, b string) int {
+	aValue, aErr := s.ParseKey(a)
+	bValue, bErr := s.ParseKey(b)
+	if aErr != nil || bErr != nil {
+		return strings.Compare(a, b)
+	}
+
+	switch aValue := aValue.(type) {
+	case time.Time:
+		return aValue.Compare(bValue.(time.Time))
+	case float64:
+		return cmp.Compare(aValue, bValue.(float64))
+	case int:
+		return cmp.Compare(aValue, bValue.(int))
+	}
+	return strings.Compare(a, b)
+}
+
+func parseTimeKey(key string) (interface{}, error) {
+	return time.Parse(time.RFC3339Nano, key)
+}
+
+func parseFloatKey(key string) (interface{}, error) {
+	return strconv.ParseFloat(key, 64)
+}
+
+func parseIntKey(key string) (interface{}, error) {
+	return strconv.Atoi(key)
+}
+
+func parseStringKey(key string) (interface{}, error) {
+	return key, nil
+}
+
+func postedAtKey(article models.Article) string {
+	return article.PostedAt.UTC().Format(time.RFC3339Nano)
+}
+
+var SortNewest = SortOrder{
+	Name:     "newest",
+	Desc:     true,
+	Key:      postedAtKey,
+	ParseKey: parseTimeKey,
+}
+
+var SortOldest = SortOrder{
+	Name:     "oldest",
+	Key:      postedAtKey,
+	ParseKey: parseTimeKey,
+}
+
+// SortLongestRead orders articles by ReadDurationMinutes
+var SortLongestRead = SortOrder{
+	Name: "longest",
+	Desc: true,
+	Key: func(article models.Article) string {
+		return strconv.Itoa(article.ReadDurationMinutes)
+	},
+	ParseKey: parseIntKey,
+}
+
+// SortTitle orders articles alphabetically. Titles compare bytewise,
+// like the "C" collation
+var SortTitle = SortOrder{
+	Name: "title",
+	Key: func(article models.Article) string {
+		return article.Title
+	},
+	ParseKey: parseStringKey,
+}
+
+// SortRelevance orders search results by SearchRank
+var SortRelevance = SortOrder{
+	Name: "relevance",
+	Desc: true,
+	Key: func(article models.Article) string {
+		return strconv.FormatFloat(article.SearchRank, 'g', -1, 64)
+	},
+	ParseKey: parseFloatKey,
+}
+
+// ArticleSorts are the sort orders article listings accept
+var ArticleSorts = map[string]SortOrder{
+	SortNewest.Name:      SortNewest,
+	SortOldest.Name:      SortOldest,
+	SortLongestRead.Name: SortLongestRead,
+	SortTitle.Name:       SortTitle,
+}
+
+// Cursor points at the article a page starts after. Backward cursors
+// walk towards the start of the listing
+type Cursor struct {
+	Sort     string `json:"s"`
+	Key      string `json:"k"`
+	ID       string `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as opaque URL safe base64
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Encode for the given sort
+func DecodeCursor[T any](encoded string, sort Sort[T]) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != sort.Name {
+		return nil, fmt.Errorf("%w: it belongs to the %q sort", ErrInvalidCursor, cursor.Sort)
+	}
+	if _, err := sort.ParseKey(cursor.Key); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+type PageRequest struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// PageInfo locates a page in its keyset listing. Total counts every
+// item matching the filter, regardless of the cursor
+type PageInfo struct {
+	Total      int64
+	NextCursor string
+	PrevCursor string
+}
+
+type ArticlePage struct {
+	Articles []models.Article
+	PageInfo
+}
+
+// NewPage trims the items fetched for a page and sets its cursors.
+// Backends fetch one item more than the limit, in walking order, so
+// hasMore tells whether the listing continues past the page
+func NewPage[T any](items []T, total int64, id func(item T) string, sort Sort[T], page PageRequest) ([]T, PageInfo) {
+	hasMore := len(items) > page.Limit
+	if hasMore {
+		items = items[:page.Limit]
+	}
+
+	backward := page.Cursor != nil && page.Cursor.Backward
+	if backward {
+		slices.Reverse(items)
+	}
+
+	info := PageInfo{Total: total}
+	if len(items) == 0 {
+		return items, info
+	}
+
+	hasNext := hasMore
+	hasPrev := page.Cursor != nil
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		last := items[len(items)-1]
+		info.NextCursor = Cursor{Sort: sort.Name, Key: sort.Key(last), ID: id(last)}.Encode()
+	}
+	if hasPrev {
+		first := items[0]
+		info.PrevCursor = Cursor{Sort: sort.Name, Key: sort.Key(first), ID: id(first), Backward: true}.Encode()
+	}
+
+	return items, info
+}
+
+func articleID(article models.Article) string { return article.ID }
+
+// NewArticlePage builds the page from the articles fetched for it,
+// see NewPage
+func NewArticlePage(articles []models.Article, total int64, sort SortOrder, page PageRequest) ArticlePage {
+	articles, info := NewPage(articles, total, articleID, sort, page)
+	return ArticlePage{Articles: articles, PageInfo: info}
+}
+
+// ArticleFilter narrows article listings, zero values match every
+// article. Tag matches the scraped display tag, TagSlug any of the
+// article's normalized tags. Ranges are inclusive, AuthorName matches
+// case-insensitively and a nil HasImage or HasLink matches either way
+type ArticleFilter struct {
+	AuthorID       string
+	AuthorName     string
+	Tag            string
+	TagSlug        string
+	SourceTag      string
+	PostedFrom     time.Time
+	PostedTo       time.Time
+	MinReadMinutes int
+	MaxReadMinutes int
+	HasImage       *bool
+	HasLink        *bool
+}
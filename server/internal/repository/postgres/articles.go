@@ -0,0 +1,454 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArticleRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleRepository(db *gorm.DB) *ArticleRepository {
+	return &ArticleRepository{db: db}
+}
+
+func (r *ArticleRepository) Create(article models.Article) (models.Article, error) {
+	result := r.db.Create(&article)
+
+	if result.Error != nil {
+		return article, translateError("article", result.Error)
+	}
+	return article, nil
+}
+
+func (r *ArticleRepository) FindOne(id string) (models.Article, error) {
+	var article models.Article
+	if err := r.db.First(&article, "id = ?", id).Error; err != nil {
+		return article, translateError("article", err)
+	}
+
+	return article, nil
+}
+
+func (r *ArticleRepository) FindByTitle(title string) (models.Article, error) {
+	var article models.Article
+	if err := r.db.First(&article, "title = ?", title).Error; err != nil {
+		return article, translateError("article", err)
+	}
+
+	return article, nil
+}
+
+func (r *ArticleRepository) FindAll(limit float64, cursor string) ([]models.Article, int64, error) {
+	var articles []models.Article
+	var count int64
+	query := r.db.Model(&models.Article{}).
+		// Preload("Author").
+		Order("\"updatedAt\" DESC").
+		Limit(int(limit))
+
+	if cursor != "" {
+		var lastArticle models.Article
+		if err := r.db.Select("\"updatedAt\"").Where("id = ?", cursor).First(&lastArticle).Error; err != nil {
+			return nil, 0, translateError("cursor article", err)
+		}
+		query = query.Where("\"updatedAt\" < ?", lastArticle.UpdatedAt)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, count, nil
+}
+
+func (r *ArticleRepository) FindPage(filter repository.ArticleFilter, sort repository.SortOrder,
+	page repository.PageRequest) (repository.ArticlePage, error) {
+	column, ok := sortColumns[sort.Name]
+	if !ok {
+		return repository.ArticlePage{}, fmt.Errorf("unsupported sort %q", sort.Name)
+	}
+
+	return findPage(func() *gorm.DB {
+		return applyArticleFilter(r.db.Model(&models.Article{}), filter)
+	}, nil, column, sort, page)
+}
+
+func (r *ArticleRepository) FindAllByPostedAtInAsc(limit int) ([]models.Article, int64, error) {
+	var articles []models.Article
+	var count int64
+	query := r.db.Model(&models.Article{}).
+		Order("\"postedAt\" ASC").
+		Limit(int(limit))
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, count, nil
+}
+
+func (r *ArticleRepository) FindAllWithWrongImage(limit float64, cursor string) ([]models.Article, int64, error) {
+	var articles []models.Article
+	query := r.db.Model(&models.Article{}).
+		Order("\"postedAt\" DESC").
+		Limit(int(limit))
+
+	wrongFormat := "?auto"
+
+	query = query.Where("\"imageUrl\" ILIKE ?", "%"+wrongFormat+"%")
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Find(&articles).Error; err != nil {
+		return articles, 0, err
+	}
+
+	return articles, count, nil
+}
+
+func (r *ArticleRepository) FindAllWithoutImageMetadata(afterID string, limit int) ([]models.Article, error) {
+	var articles []models.Article
+	query := r.db.Model(&models.Article{}).
+		Where("\"imageBlurHash\" IS NULL AND \"imageUrl\" <> '' AND \"imageMetadataFailedAt\" IS NULL").
+		Order("id").
+		Limit(limit)
+	if afterID != "" {
+		query = query.Where("id > ?", afterID)
+	}
+
+	if err := query.Find(&articles).Error; err != nil {
+		return articles, err
+	}
+	return articles, nil
+}
+
+func (r *ArticleRepository) MarkImageMetadataFailed(id string) error {
+	result := r.db.Model(&models.Article{}).
+		Where("id = ?", id).
+		UpdateColumn("imageMetadataFailedAt", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("article %w", models.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *ArticleRepository) FindCount() (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Article{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// searchHeadlineOptions configures ts_headline, matches are wrapped
+// in <mark> so clients can style them
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+// escapeHTMLSQL wraps a text expression so it's HTML escaped the same
+// way html.EscapeString does. Scraped titles and bodies are escaped
+// before ts_headline adds its <mark> tags, clients render the
+// highlight as HTML
+func escapeHTMLSQL(expr string) string {
+	return "replace(replace(replace(replace(replace(" + expr +
+		", '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '\"', '&#34;'), '''', '&#39;')"
+}
+
+// Search ranks articles matching the full-text query with ts_rank,
+// title matches weigh the most followed by tag and author name.
+// Fuzzy matching uses the pg_trgm % operator so the title trigram
+// index applies, the threshold is set for the transaction only
+func (r *ArticleRepository) Search(options repository.SearchOptions) (repository.ArticlePage, error) {
+	tsQuery := repository.ParseSearchQuery(options.Query).TSQuery()
+	if tsQuery == "" && !options.Fuzzy {
+		return repository.ArticlePage{Articles: []models.Article{}}, nil
+	}
+
+	var result repository.ArticlePage
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var conditions []string
+		var args []interface{}
+		rank := clause.Expr{SQL: "0"}
+
+		if tsQuery != "" {
+			conditions = append(conditions, "\"searchVector\" @@ to_tsquery('english', ?)")
+			args = append(args, tsQuery)
+			rank.SQL = "ts_rank(\"searchVector\", to_tsquery('english', ?))"
+			rank.Vars = append(rank.Vars, tsQuery)
+		}
+		if options.Fuzzy {
+			if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)",
+				strconv.FormatFloat(options.FuzzyThreshold, 'f', -1, 64)).Error; err != nil {
+				return err
+			}
+			conditions = append(conditions, "title % ?")
+			args = append(args, options.Query)
+			rank.SQL += " + similarity(title, ?)"
+			rank.Vars = append(rank.Vars, options.Query)
+		}
+		// ts_rank and similarity return real, comparing as double
+		// precision keeps cursor keys exact
+		rank.SQL = "(" + rank.SQL + ")::float8"
+
+		// ts_headline needs a tsquery, fuzzy only matches come back
+		// with the escaped title
+		highlight := escapeHTMLSQL("title")
+		var highlightArgs []interface{}
+		if tsQuery != "" {
+			highlight = "ts_headline('english', " + escapeHTMLSQL("concat_ws(' ', title, body)") +
+				", to_tsquery('english', ?), ?)"
+			highlightArgs = append(highlightArgs, tsQuery, searchHeadlineOptions)
+		}
+
+		selects := clause.Expr{
+			SQL:  "articles.*, " + rank.SQL + " AS \"searchRank\", " + highlight + " AS \"searchHighlight\"",
+			Vars: append(append([]interface{}{}, rank.Vars...), highlightArgs...),
+		}
+
+		var err error
+		result, err = findPage(func() *gorm.DB {
+			return tx.Model(&models.Article{}).
+				Where("("+strings.Join(conditions, " OR ")+")", args...)
+		}, &selects, rank, repository.SortRelevance, options.Page)
+		return err
+	})
+	if err != nil {
+		return repository.ArticlePage{}, err
+	}
+
+	return result, nil
+}
+
+// DidYouMean returns the title with the best word similarity to the
+// query, or an empty string when none reaches the threshold
+func (r *ArticleRepository) DidYouMean(searchQuery string, threshold float64) (string, error) {
+	var titles []string
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)",
+			strconv.FormatFloat(threshold, 'f', -1, 64)).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Article{}).
+			Where("? <% title", searchQuery).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "word_similarity(?, title) DESC, \"postedAt\" DESC",
+				Vars: []interface{}{searchQuery},
+			}}).
+			Limit(1).
+			Pluck("title", &titles).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(titles) == 0 {
+		return "", nil
+	}
+
+	return titles[0], nil
+}
+
+func (r *ArticleRepository) SearchByTagIndex(searchQuery string) ([]models.Article, int64, error) {
+	var articles []models.Article
+	query := r.db.Model(&models.Article{}).
+		Preload("Author").
+		Order("\"postedAt\" DESC")
+
+	// query = query.Where("\"tagIndex\" ILIKE ?", "%"+searchQuery+"%")
+	query = query.Where("\"tagIndex\" = ?", searchQuery)
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Find(&articles).Error; err != nil {
+		return articles, 0, err
+	}
+
+	return articles, count, nil
+}
+
+func (r *ArticleRepository) FindByTagIndex(tagIndex string) (models.Article, error) {
+	var article models.Article
+	err := r.db.Preload("Author").
+		Order("\"postedAt\" DESC").
+		First(&article, "\"tagIndex\" = ?", tagIndex).Error
+	if err != nil {
+		return article, translateError("article", err)
+	}
+
+	return article, nil
+}
+
+func (r *ArticleRepository) FindNeighbours(article models.Article) (repository.ArticleNeighbours, error) {
+	var neighbours repository.ArticleNeighbours
+
+	find := func(operator, direction string) (*models.Article, error) {
+		var neighbour []models.Article
+		err := r.db.Preload("Author").
+			Where(fmt.Sprintf("(\"postedAt\", id) %s (?, ?)", operator), article.PostedAt, article.ID).
+			Order(fmt.Sprintf("\"postedAt\" %s, id %s", direction, direction)).
+			Limit(1).
+			Find(&neighbour).Error
+		if err != nil || len(neighbour) == 0 {
+			return nil, err
+		}
+		return &neighbour[0], nil
+	}
+
+	var err error
+	if neighbours.Previous, err = find("<", "DESC"); err != nil {
+		return neighbours, translateError("article", err)
+	}
+	if neighbours.Next, err = find(">", "ASC"); err != nil {
+		return neighbours, translateError("article", err)
+	}
+
+	return neighbours, nil
+}
+
+func (r *ArticleRepository) IncrementClickCount(id string) error {
+	result := r.db.Model(&models.Article{}).
+		Where("id = ?", id).
+		UpdateColumn("clickCount", gorm.Expr("\"clickCount\" + 1"))
+	if result.Error != nil {
+		return translateError("article", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("article %w", models.ErrNotFound)
+	}
+
+	return nil
+}
+
+// postedAtDay is the calendar day of postedAt in the zone bound to it
+const postedAtDay = "(\"postedAt\" AT TIME ZONE ?)::date"
+
+func (r *ArticleRepository) FindByPostedAt(date time.Time, location *time.Location) ([]models.Article, error) {
+	var articles []models.Article
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Zones are at most 14 hours off UTC, the range keeps the postedAt
+	// index usable before the exact day check
+	err := r.db.Model(&models.Article{}).
+		Preload("Author").
+		Where("\"postedAt\" >= ? AND \"postedAt\" < ?", day.Add(-14*time.Hour), day.Add(38*time.Hour)).
+		Where(postedAtDay+" = ?::date", location.String(), day.Format(time.DateOnly)).
+		Order("\"postedAt\" DESC").
+		Find(&articles).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+func (r *ArticleRepository) FindArticleCountPerDay(limit int, dateCursor time.Time,
+	location *time.Location) ([]map[string]interface{}, error) {
+	var results []struct {
+		Date  time.Time `json:"date"`
+		Count int64     `json:"count"`
+	}
+
+	tracked, err := r.dayCountsTracked(location)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Table("daily_article_counts").
+		Select("\"day\" AS date, \"count\"").
+		Where("\"timeZone\" = ?", location.String()).
+		Order("date DESC").
+		Limit(limit)
+	if !dateCursor.IsZero() {
+		query = query.Where("\"day\" < ?::date", dateCursor.Format(time.DateOnly))
+	}
+
+	if !tracked {
+		query = r.db.Model(&models.Article{}).
+			Select(postedAtDay+" AS date, COUNT(*) AS count", location.String()).
+			Group("date").
+			Order("date DESC").
+			Limit(limit)
+		if !dateCursor.IsZero() {
+			query = query.Where(postedAtDay+" < ?::date", location.String(), dateCursor.Format(time.DateOnly))
+		}
+	}
+
+	if err := query.Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	dayCounts := make([]repository.DayCount, len(results))
+	for i, result := range results {
+		dayCounts[i] = repository.DayCount{Date: result.Date, Count: result.Count}
+	}
+
+	return repository.FillMissingDays(dayCounts), nil
+}
+
+func (r *ArticleRepository) CountDistinctDays(count *int64, location *time.Location) error {
+	tracked, err := r.dayCountsTracked(location)
+	if err != nil {
+		return err
+	}
+	if tracked {
+		return r.db.Table("daily_article_counts").
+			Where("\"timeZone\" = ?", location.String()).
+			Count(count).Error
+	}
+
+	return r.db.Model(&models.Article{}).
+		Select("COUNT(DISTINCT "+postedAtDay+")", location.String()).
+		Row().Scan(count)
+}
+
+func (r *ArticleRepository) Update(article models.Article) (models.Article, error) {
+	if err := r.db.Save(&article).Error; err != nil {
+		return article, translateError("article", err)
+	}
+
+	news, err := r.FindOne(article.ID)
+	if err != nil {
+		return news, err
+	}
+
+	return news, nil
+}
+
+func (r *ArticleRepository) Delete(id string) error {
+	result := r.db.Unscoped().Where("id = ?", id).Delete(&models.Article{})
+	if result.Error != nil {
+		return translateError("article", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("article %w", models.ErrNotFound)
+	}
+
+	return nil
+}
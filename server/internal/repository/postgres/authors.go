@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AuthorRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthorRepository(db *gorm.DB) *AuthorRepository {
+	return &AuthorRepository{db: db}
+}
+
+func (r *AuthorRepository) Create(author models.Author) (models.Author, error) {
+	result := r.db.Create(&author)
+
+	if result.Error != nil {
+		return author, translateError("author", result.Error)
+	}
+	return author, nil
+}
+
+func (r *AuthorRepository) FindOne(id string) (models.Author, error) {
+	var author models.Author
+	if err := r.db.First(&author, "id = ?", id).Error; err != nil {
+		return author, translateError("author", err)
+	}
+
+	return author, nil
+}
+
+func (r *AuthorRepository) FindByName(name string) (models.Author, error) {
+	var author models.Author
+	if err := r.db.First(&author, "name = ?", name).Error; err != nil {
+		return author, translateError("author", err)
+	}
+
+	return author, nil
+}
+
+func (r *AuthorRepository) FindByPage(pageURL string) (models.Author, error) {
+	var author models.Author
+	if err := r.db.First(&author, "\"pageUrl\" = ?", pageURL).Error; err != nil {
+		return author, translateError("author", err)
+	}
+
+	return author, nil
+}
+
+func (r *AuthorRepository) FindAll(limit float64, cursor string) ([]models.Author, error) {
+	var authors []models.Author
+	query := r.db.Order("\"updatedAt\" DESC").Limit(int(limit))
+
+	if cursor != "" {
+		var lastAuthor models.Author
+		if err := r.db.Select("\"updatedAt\"").Where("id = ?", cursor).First(&lastAuthor).Error; err != nil {
+			return nil, translateError("cursor author", err)
+		}
+		query = query.Where("\"updatedAt\" < ?", lastAuthor.UpdatedAt)
+	}
+	if err := query.Find(&authors).Error; err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+// authorSortColumns holds the SQL expression behind every author sort
+// order, over the article stats joined by FindPage
+var authorSortColumns = map[string]clause.Expr{
+	repository.SortMostArticles.Name: {SQL: "coalesce(stats.\"articleCount\", 0)"},
+	repository.SortLatestPost.Name:   {SQL: "coalesce(stats.\"latestPostedAt\", 'epoch'::timestamptz)"},
+	repository.SortAuthorName.Name:   {SQL: "authors.name COLLATE \"C\""},
+}
+
+func (r *AuthorRepository) FindPage(filter repository.AuthorFilter, sort repository.AuthorSortOrder,
+	page repository.PageRequest) (repository.AuthorPage, error) {
+	column, ok := authorSortColumns[sort.Name]
+	if !ok {
+		return repository.AuthorPage{}, fmt.Errorf("unsupported sort %q", sort.Name)
+	}
+
+	base := func() *gorm.DB {
+		query := r.db.Model(&models.Author{})
+		if filter.Search != "" {
+			query = query.Where("authors.name ILIKE ?", "%"+escapeLike(filter.Search)+"%")
+		}
+		return query
+	}
+
+	var total int64
+	if err := base().Count(&total).Error; err != nil {
+		return repository.AuthorPage{}, err
+	}
+
+	query := base().
+		Select("authors.*, coalesce(stats.\"articleCount\", 0) AS \"articleCount\", stats.\"latestPostedAt\"").
+		Joins("LEFT JOIN (SELECT \"authorID\", count(*) AS \"articleCount\", max(\"postedAt\") AS \"latestPostedAt\"" +
+			" FROM articles GROUP BY \"authorID\") stats ON stats.\"authorID\" = authors.id")
+	query, err := applyKeyset(query, column, "authors.id", sort, page)
+	if err != nil {
+		return repository.AuthorPage{}, err
+	}
+
+	authors := []models.Author{}
+	if err := query.Find(&authors).Error; err != nil {
+		return repository.AuthorPage{}, err
+	}
+
+	return repository.NewAuthorPage(authors, total, sort, page), nil
+}
+
+func (r *AuthorRepository) FindStats(id string) (repository.AuthorStats, error) {
+	var stats repository.AuthorStats
+
+	err := r.db.Model(&models.Article{}).
+		Select("count(*) AS \"articleCount\", min(\"postedAt\") AS \"firstPostedAt\", "+
+			"max(\"postedAt\") AS \"lastPostedAt\", "+
+			"coalesce(avg(\"readDurationMinutes\") FILTER (WHERE \"readDurationMinutes\" > 0), 0)::float8 AS \"averageReadMinutes\"").
+		Where("\"authorID\" = ?", id).
+		Scan(&stats).Error
+	if err != nil {
+		return stats, translateError("author stats", err)
+	}
+
+	stats.Tags = []repository.TagCount{}
+	err = r.db.Model(&models.Article{}).
+		Select("tag, count(*) AS count").
+		Where("\"authorID\" = ?", id).
+		Group("tag").
+		Order("count DESC, tag").
+		Scan(&stats.Tags).Error
+	if err != nil {
+		return stats, translateError("author stats", err)
+	}
+
+	return stats, nil
+}
+
+func (r *AuthorRepository) Update(author models.Author) (models.Author, error) {
+	if err := r.db.Save(&author).Error; err != nil {
+		return author, translateError("author", err)
+	}
+
+	author, err := r.FindOne(author.ID)
+	if err != nil {
+		return author, err
+	}
+
+	return author, nil
+}
+
+func (r *AuthorRepository) Delete(id string) error {
+	result := r.db.Unscoped().Where("id = ?", id).Delete(&models.Author{})
+	if result.Error != nil {
+		return translateError("author", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("author %w", models.ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"gorm.io/gorm"
+)
+
+// The daily_article_counts rollup is kept in sync by triggers on
+// articles, see migration 0009. It holds the zones in
+// daily_article_count_zones only, UTC and the zones added with the
+// rebuild-day-counts command. Requests never add zones
+
+// dayCountsTracked reports whether the rollup covers location, other
+// zones are counted from the articles table
+func (r *ArticleRepository) dayCountsTracked(location *time.Location) (bool, error) {
+	var tracked int64
+	err := r.db.Table("daily_article_count_zones").
+		Where("\"timeZone\" = ?", location.String()).
+		Count(&tracked).Error
+	return tracked > 0, err
+}
+
+// RebuildDayCounts adds zones to the rollup and recounts the days of
+// every tracked zone
+func (r *ArticleRepository) RebuildDayCounts(zones []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var tracked []string
+		if err := tx.Table("daily_article_count_zones").Pluck("timeZone", &tracked).Error; err != nil {
+			return err
+		}
+		for _, zone := range zones {
+			if !slices.Contains(tracked, zone) {
+				tracked = append(tracked, zone)
+			}
+		}
+		if len(tracked) > constants.DAY_COUNT_MAX_ZONES {
+			return fmt.Errorf("day counts are kept for at most %d zones, got %d",
+				constants.DAY_COUNT_MAX_ZONES, len(tracked))
+		}
+
+		for _, zone := range tracked {
+			if err := rebuildDayCounts(tx, zone); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// rebuildDayCounts tracks zone and recounts its days. Writes to
+// articles wait for the transaction so no article is missed
+func rebuildDayCounts(tx *gorm.DB, zone string) error {
+	if err := tx.Exec("LOCK TABLE articles IN SHARE MODE").Error; err != nil {
+		return err
+	}
+	err := tx.Exec("INSERT INTO daily_article_count_zones (\"timeZone\") VALUES (?) ON CONFLICT DO NOTHING", zone).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec("SELECT rebuild_daily_article_counts(?)", zone).Error
+}
+
+func (r *ArticleRepository) FindAuthorDayCounts(location *time.Location) ([]repository.AuthorDayCount, error) {
+	var rows []struct {
+		Day        time.Time `gorm:"column:day"`
+		AuthorID   string    `gorm:"column:authorID"`
+		AuthorName string    `gorm:"column:authorName"`
+		Count      int64     `gorm:"column:count"`
+	}
+
+	err := r.db.Model(&models.Article{}).
+		Select("(articles.\"postedAt\" AT TIME ZONE ?)::date AS day, articles.\"authorID\", authors.name AS \"authorName\", count(*) AS count",
+			location.String()).
+		Joins("JOIN authors ON authors.id = articles.\"authorID\"").
+		Where("articles.\"postedAt\" IS NOT NULL").
+		Group("1, 2, 3").
+		Order("1, 2").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	dayCounts := make([]repository.AuthorDayCount, len(rows))
+	for i, row := range rows {
+		dayCounts[i] = repository.AuthorDayCount(row)
+	}
+	return dayCounts, nil
+}
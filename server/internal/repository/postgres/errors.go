@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const uniqueViolationCode = "23505"
+
+// translateError maps gorm and Postgres errors onto the model
+// sentinel errors. Constraint violations are logged here and left
+// out of the returned error so database detail never reaches clients
+func translateError(entity string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%s %w", entity, models.ErrNotFound)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		log.Printf("%s conflict: %v", entity, err)
+		return fmt.Errorf("%s %w", entity, models.ErrConflict)
+	}
+
+	return fmt.Errorf("%s: %w", entity, err)
+}
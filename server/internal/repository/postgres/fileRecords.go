@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"gorm.io/gorm"
+)
+
+type FileRecordRepository struct {
+	db *gorm.DB
+}
+
+func NewFileRecordRepository(db *gorm.DB) *FileRecordRepository {
+	return &FileRecordRepository{db: db}
+}
+
+func (r *FileRecordRepository) Create(fileRecord models.FileRecord) (models.FileRecord, error) {
+	result := r.db.Create(&fileRecord)
+
+	if result.Error != nil {
+		return fileRecord, translateError("file record", result.Error)
+	}
+	return fileRecord, nil
+}
+
+func (r *FileRecordRepository) FindByFilename(filename string) (models.FileRecord, error) {
+	var fileRecord models.FileRecord
+	if err := r.db.First(&fileRecord, "filename = ?", filename).Error; err != nil {
+		return fileRecord, translateError("file record", err)
+	}
+
+	return fileRecord, nil
+}
+
+func (r *FileRecordRepository) Delete(id string) error {
+	result := r.db.Unscoped().Where("id = ?", id).Delete(&models.FileRecord{})
+	if result.Error != nil {
+		return translateError("file record", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("file record %w", models.ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sortColumns holds the SQL expression behind every sort order
+var sortColumns = map[string]clause.Expr{
+	repository.SortNewest.Name:      {SQL: "articles.\"postedAt\""},
+	repository.SortOldest.Name:      {SQL: "articles.\"postedAt\""},
+	repository.SortLongestRead.Name: {SQL: "coalesce(articles.\"readDurationMinutes\", 0)"},
+	repository.SortTitle.Name:       {SQL: "articles.title COLLATE \"C\""},
+}
+
+// findPage runs a keyset query on (sort column, id) over the articles
+// matching base, which is called once for the total and once for the
+// page. selects, when given, replaces the page's SELECT list
+func findPage(base func() *gorm.DB, selects *clause.Expr, column clause.Expr, sort repository.SortOrder,
+	page repository.PageRequest) (repository.ArticlePage, error) {
+	var total int64
+	if err := base().Count(&total).Error; err != nil {
+		return repository.ArticlePage{}, err
+	}
+
+	query := base().Preload("Author")
+	if selects != nil {
+		query = query.Select(selects.SQL, selects.Vars...)
+	}
+
+	query, err := applyKeyset(query, column, "articles.id", sort, page)
+	if err != nil {
+		return repository.ArticlePage{}, err
+	}
+
+	articles := []models.Article{}
+	if err := query.Find(&articles).Error; err != nil {
+		return repository.ArticlePage{}, err
+	}
+
+	return repository.NewArticlePage(articles, total, sort, page), nil
+}
+
+// applyKeyset starts the query after the page's cursor on (column,
+// idColumn), orders it the way the page walks and fetches one row
+// more than the limit
+func applyKeyset[T any](query *gorm.DB, column clause.Expr, idColumn string, sort repository.Sort[T],
+	page repository.PageRequest) (*gorm.DB, error) {
+	// Walking backward flips both the comparison and the order, the
+	// page is put back in sort order afterwards
+	desc := sort.Desc
+	if page.Cursor != nil && page.Cursor.Backward {
+		desc = !desc
+	}
+	operator, direction := ">", "ASC"
+	if desc {
+		operator, direction = "<", "DESC"
+	}
+
+	if page.Cursor != nil {
+		key, err := sort.ParseKey(page.Cursor.Key)
+		if err != nil {
+			return nil, repository.ErrInvalidCursor
+		}
+		vars := append(append([]interface{}{}, column.Vars...), key, page.Cursor.ID)
+		query = query.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", column.SQL, idColumn, operator), vars...)
+	}
+
+	return query.
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  fmt.Sprintf("%s %s, %s %s", column.SQL, direction, idColumn, direction),
+			Vars: column.Vars,
+		}}).
+		Limit(page.Limit + 1), nil
+}
+
+// applyArticleFilter adds the filter's conditions to the query
+func applyArticleFilter(query *gorm.DB, filter repository.ArticleFilter) *gorm.DB {
+	if filter.AuthorID != "" {
+		query = query.Where("articles.\"authorID\" = ?", filter.AuthorID)
+	}
+	if filter.AuthorName != "" {
+		query = query.Where("articles.\"authorID\" IN (SELECT id FROM authors WHERE lower(name) = lower(?))",
+			filter.AuthorName)
+	}
+	if filter.Tag != "" {
+		query = query.Where("articles.tag = ?", filter.Tag)
+	}
+	if filter.TagSlug != "" {
+		query = query.Where("articles.id IN (SELECT article_tags.\"articleID\" FROM article_tags"+
+			" JOIN tags ON tags.id = article_tags.\"tagID\" WHERE tags.slug = ?)", filter.TagSlug)
+	}
+	if filter.SourceTag != "" {
+		query = query.Where("articles.\"sourceTag\" = ?", filter.SourceTag)
+	}
+	if !filter.PostedFrom.IsZero() {
+		query = query.Where("articles.\"postedAt\" >= ?", filter.PostedFrom)
+	}
+	if !filter.PostedTo.IsZero() {
+		query = query.Where("articles.\"postedAt\" <= ?", filter.PostedTo)
+	}
+	if filter.MinReadMinutes > 0 {
+		query = query.Where("coalesce(articles.\"readDurationMinutes\", 0) >= ?", filter.MinReadMinutes)
+	}
+	if filter.MaxReadMinutes > 0 {
+		query = query.Where("coalesce(articles.\"readDurationMinutes\", 0) <= ?", filter.MaxReadMinutes)
+	}
+	if filter.HasImage != nil {
+		hasImage := "coalesce(articles.\"imageUrl\", '') <> ''"
+		if !*filter.HasImage {
+			hasImage = "NOT (" + hasImage + ")"
+		}
+		query = query.Where(hasImage)
+	}
+	if filter.HasLink != nil {
+		hasLink := "coalesce(articles.href, '') <> ''"
+		if !*filter.HasLink {
+			hasLink = "NOT (" + hasLink + ")"
+		}
+		query = query.Where(hasLink)
+	}
+	return query
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"gorm.io/gorm"
+)
+
+type PendingUploadRepository struct {
+	db *gorm.DB
+}
+
+func NewPendingUploadRepository(db *gorm.DB) *PendingUploadRepository {
+	return &PendingUploadRepository{db: db}
+}
+
+func (r *PendingUploadRepository) Create(pendingUpload models.PendingUpload) (models.PendingUpload, error) {
+	if err := r.db.Create(&pendingUpload).Error; err != nil {
+		return pendingUpload, translateError("pending upload", err)
+	}
+	return pendingUpload, nil
+}
+
+func (r *PendingUploadRepository) FindByKey(key string) (models.PendingUpload, error) {
+	var pendingUpload models.PendingUpload
+	if err := r.db.First(&pendingUpload, "\"key\" = ?", key).Error; err != nil {
+		return pendingUpload, translateError("pending upload", err)
+	}
+	return pendingUpload, nil
+}
+
+func (r *PendingUploadRepository) FindExpired(before time.Time, limit int) ([]models.PendingUpload, error) {
+	var pendingUploads []models.PendingUpload
+	err := r.db.Where("\"expiresAt\" < ?", before).
+		Order("\"expiresAt\"").
+		Limit(limit).
+		Find(&pendingUploads).Error
+	return pendingUploads, err
+}
+
+func (r *PendingUploadRepository) Delete(key string) error {
+	result := r.db.Where("\"key\" = ?", key).Delete(&models.PendingUpload{})
+	if result.Error != nil {
+		return translateError("pending upload", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("pending upload %w", models.ErrNotFound)
+	}
+	return nil
+}
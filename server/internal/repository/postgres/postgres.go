@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"gorm.io/gorm"
+)
+
+// NewRepositories returns the Postgres backed repositories
+func NewRepositories(db *gorm.DB) repository.Repositories {
+	return repository.Repositories{
+		Articles:       NewArticleRepository(db),
+		Authors:        NewAuthorRepository(db),
+		Tags:           NewTagRepository(db),
+		FileRecords:    NewFileRecordRepository(db),
+		PendingUploads: NewPendingUploadRepository(db),
+		Snapshots:      NewSnapshotRepository(db),
+	}
+}
+
+var (
+	_ repository.ArticleRepository       = (*ArticleRepository)(nil)
+	_ repository.AuthorRepository        = (*AuthorRepository)(nil)
+	_ repository.TagRepository           = (*TagRepository)(nil)
+	_ repository.FileRecordRepository    = (*FileRecordRepository)(nil)
+	_ repository.PendingUploadRepository = (*PendingUploadRepository)(nil)
+	_ repository.SnapshotRepository      = (*SnapshotRepository)(nil)
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes user input match literally inside a LIKE pattern
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
@@ -0,0 +1,48 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/migrations"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/contract"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository/postgres"
+	gormPostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestContract runs against the scratch database in
+// HACKERNOON_TEST_DSN. It truncates every table so it must never
+// point at real data
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("HACKERNOON_TEST_DSN")
+	if dsn == "" {
+		t.Skip("HACKERNOON_TEST_DSN is not set")
+	}
+
+	db, err := gorm.Open(gormPostgres.Open(dsn), &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to the test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get database handle: %v", err)
+	}
+	migrator, err := migrations.NewMigrator(sqlDB)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("Failed to migrate the test database: %v", err)
+	}
+
+	contract.Run(t, func() (repository.Repositories, error) {
+		// TRUNCATE skips the row triggers, the day count rollup is
+		// emptied along with the articles
+		err := db.Exec("TRUNCATE articles, authors, file_records, pending_uploads, tags, article_tags, " +
+			"daily_article_counts").Error
+		return postgres.NewRepositories(db), err
+	})
+}
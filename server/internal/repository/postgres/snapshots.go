@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewSnapshotRepository(db *gorm.DB) *SnapshotRepository {
+	return &SnapshotRepository{db: db}
+}
+
+// snapshotColumns are the columns Restore overwrites when the row
+// already exists. readDurationMinutes and the search vectors are
+// generated, clickCount is restored on its own
+var snapshotColumns = map[string][]string{
+	"authors": {"name", "avatarUrl", "avatarFilename", "pageUrl", "createdAt", "updatedAt"},
+	"articles": {
+		"authorID", "tag", "tagIndex", "title", "href", "imageUrl", "imageFilename", "imageWidth",
+		"imageHeight", "imageAspectRatio", "imageDominantColor", "imageBlurHash", "imageMetadataFailedAt",
+		"postedAt",
+		"readDuration", "sourceTag", "body", "createdAt", "updatedAt",
+	},
+	"tags": {"slug", "name", "aliases", "createdAt", "updatedAt"},
+	"file_records": {
+		"url", "filename", "originalName", "size", "contentType", "width", "height",
+		"dominantColor", "blurHash", "uploadedBy", "createdAt", "updatedAt",
+	},
+}
+
+// Dump reads every table in one REPEATABLE READ transaction, paging
+// through each by primary key
+func (r *SnapshotRepository) Dump(batchSize int, handle func(batch repository.SnapshotBatch) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY").Error; err != nil {
+			return err
+		}
+
+		err := dumpTable(tx, batchSize, []string{"id"},
+			func(author models.Author) []interface{} { return []interface{}{author.ID} },
+			func(rows []models.Author) error { return handle(repository.SnapshotBatch{Authors: rows}) })
+		if err != nil {
+			return err
+		}
+		err = dumpTable(tx, batchSize, []string{"id"},
+			func(article models.Article) []interface{} { return []interface{}{article.ID} },
+			func(rows []models.Article) error { return handle(repository.SnapshotBatch{Articles: rows}) })
+		if err != nil {
+			return err
+		}
+		err = dumpTable(tx, batchSize, []string{"id"},
+			func(tag models.Tag) []interface{} { return []interface{}{tag.ID} },
+			func(rows []models.Tag) error { return handle(repository.SnapshotBatch{Tags: rows}) })
+		if err != nil {
+			return err
+		}
+		err = dumpTable(tx, batchSize, []string{"articleID", "tagID"},
+			func(articleTag models.ArticleTag) []interface{} {
+				return []interface{}{articleTag.ArticleID, articleTag.TagID}
+			},
+			func(rows []models.ArticleTag) error { return handle(repository.SnapshotBatch{ArticleTags: rows}) })
+		if err != nil {
+			return err
+		}
+		return dumpTable(tx, batchSize, []string{"id"},
+			func(fileRecord models.FileRecord) []interface{} { return []interface{}{fileRecord.ID} },
+			func(rows []models.FileRecord) error { return handle(repository.SnapshotBatch{FileRecords: rows}) })
+	})
+}
+
+// dumpTable pages through a table by its key columns with row value
+// comparisons, e.g ("articleID", "tagID") > ($1, $2)
+func dumpTable[T any](tx *gorm.DB, batchSize int, keyColumns []string,
+	key func(row T) []interface{}, handle func(rows []T) error) error {
+	quoted := make([]string, len(keyColumns))
+	placeholders := make([]string, len(keyColumns))
+	for i, column := range keyColumns {
+		quoted[i] = `"` + column + `"`
+		placeholders[i] = "?"
+	}
+	order := strings.Join(quoted, ", ")
+	after := fmt.Sprintf("(%s) > (%s)", order, strings.Join(placeholders, ", "))
+
+	var lastKey []interface{}
+	for {
+		query := tx.Order(order).Limit(batchSize)
+		if lastKey != nil {
+			query = query.Where(after, lastKey...)
+		}
+
+		var rows []T
+		if err := query.Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := handle(rows); err != nil {
+			return err
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+		lastKey = key(rows[len(rows)-1])
+	}
+}
+
+// Restore writes the batch in one transaction. Hooks are skipped so
+// the ids in the snapshot are kept
+func (r *SnapshotRepository) Restore(batch repository.SnapshotBatch) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.Session(&gorm.Session{SkipHooks: true})
+
+		if len(batch.Authors) > 0 {
+			if err := upsertByID(tx, "authors").Create(&batch.Authors).Error; err != nil {
+				return translateError("author", err)
+			}
+		}
+		if len(batch.Articles) > 0 {
+			if err := upsertByID(tx, "articles").Create(&batch.Articles).Error; err != nil {
+				return translateError("article", err)
+			}
+			if err := restoreClickCounts(tx, batch.Articles); err != nil {
+				return err
+			}
+		}
+		if len(batch.Tags) > 0 {
+			if err := upsertByID(tx, "tags").Create(&batch.Tags).Error; err != nil {
+				return translateError("tag", err)
+			}
+		}
+		if len(batch.ArticleTags) > 0 {
+			err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&batch.ArticleTags).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(batch.FileRecords) > 0 {
+			if err := upsertByID(tx, "file_records").Create(&batch.FileRecords).Error; err != nil {
+				return translateError("file record", err)
+			}
+		}
+		return nil
+	})
+}
+
+func upsertByID(tx *gorm.DB, table string) *gorm.DB {
+	return tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(snapshotColumns[table]),
+	})
+}
+
+// restoreClickCounts sets clickCount, which the model only reads
+func restoreClickCounts(tx *gorm.DB, articles []models.Article) error {
+	ids := make([]string, len(articles))
+	counts := make([]int64, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+		counts[i] = int64(article.ClickCount)
+	}
+
+	return tx.Exec(`UPDATE articles SET "clickCount" = counts.count
+FROM unnest(CAST(@ids AS uuid[]), CAST(@counts AS integer[])) AS counts(id, count)
+WHERE articles.id = counts.id AND articles."clickCount" <> counts.count`,
+		map[string]interface{}{"ids": pq.Array(ids), "counts": pq.Array(counts)}).Error
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Stream reads the articles through a server side cursor, FETCHing
+// one batch at a time so memory stays flat however many match
+func (r *ArticleRepository) Stream(filter repository.ArticleFilter, batchSize int,
+	handle func(articles []models.Article) error) error {
+	// The filtered query is built, not run, and declared as a cursor
+	statement := applyArticleFilter(r.db.Session(&gorm.Session{DryRun: true}).Model(&models.Article{}), filter).
+		Order("articles.\"postedAt\" DESC, articles.id DESC").
+		Find(&[]models.Article{}).Statement
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("DECLARE article_stream NO SCROLL CURSOR FOR "+statement.SQL.String(), statement.Vars...).Error
+		if err != nil {
+			return err
+		}
+
+		for {
+			var articles []models.Article
+			err := tx.Raw(fmt.Sprintf("FETCH FORWARD %d FROM article_stream", batchSize)).Scan(&articles).Error
+			if err != nil {
+				return err
+			}
+			if len(articles) == 0 {
+				return tx.Exec("CLOSE article_stream").Error
+			}
+
+			if err := loadAuthors(tx, articles); err != nil {
+				return err
+			}
+			if err := handle(articles); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// loadAuthors sets the Author of every article with one query, the
+// cursor rules out Preload
+func loadAuthors(tx *gorm.DB, articles []models.Article) error {
+	authorIDs := make([]string, 0, len(articles))
+	for _, article := range articles {
+		authorIDs = append(authorIDs, article.AuthorID)
+	}
+
+	var authors []models.Author
+	if err := tx.Where("id IN ?", authorIDs).Find(&authors).Error; err != nil {
+		return err
+	}
+	byID := make(map[string]*models.Author, len(authors))
+	for i := range authors {
+		byID[authors[i].ID] = &authors[i]
+	}
+	for i := range articles {
+		articles[i].Author = byID[articles[i].AuthorID]
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// findBySlug matches the slug or a spelling in the aliases, preferring
+// the tag owning the slug
+func (r *TagRepository) findBySlug(slug, spelling string) (models.Tag, error) {
+	var tag models.Tag
+	err := r.db.
+		Where("slug = ? OR ? = ANY(aliases)", slug, spelling).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "slug = ? DESC", Vars: []interface{}{slug}}}).
+		Take(&tag).Error
+	if err != nil {
+		return tag, translateError("tag", err)
+	}
+
+	return tag, nil
+}
+
+func (r *TagRepository) FindOrCreate(name string) (models.Tag, error) {
+	slug := models.TagSlug(name)
+	if slug == "" {
+		return models.Tag{}, fmt.Errorf("tag %q has no letters or digits", name)
+	}
+
+	spelling := models.TagName(name)
+
+	tag, err := r.findBySlug(slug, spelling)
+	if err == nil {
+		return r.addAlias(tag, spelling)
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return tag, err
+	}
+
+	tag = models.Tag{Slug: slug, Name: spelling, Aliases: []string{}}
+	if err := r.db.Create(&tag).Error; err != nil {
+		err = translateError("tag", err)
+		// Another ingestion created it meanwhile
+		if errors.Is(err, models.ErrConflict) {
+			tag, err = r.findBySlug(slug, spelling)
+			if err != nil {
+				return tag, err
+			}
+			return r.addAlias(tag, spelling)
+		}
+		return tag, err
+	}
+
+	return tag, nil
+}
+
+// addAlias records spelling as an alias of the tag unless it's the
+// tag's name or already recorded
+func (r *TagRepository) addAlias(tag models.Tag, spelling string) (models.Tag, error) {
+	if spelling == tag.Name || slices.Contains(tag.Aliases, spelling) {
+		return tag, nil
+	}
+
+	err := r.db.Model(&tag).
+		Where("NOT (? = ANY(aliases))", spelling).
+		Updates(map[string]interface{}{
+			"aliases":   gorm.Expr("array_append(aliases, ?)", spelling),
+			"updatedAt": time.Now(),
+		}).Error
+	if err != nil {
+		return tag, translateError("tag", err)
+	}
+
+	return r.findBySlug(tag.Slug, spelling)
+}
+
+func (r *TagRepository) FindBySlug(slug string) (models.Tag, error) {
+	return r.findBySlug(models.TagSlug(slug), models.TagName(slug))
+}
+
+// tagSortColumns holds the SQL expression behind every tag sort order,
+// over the article counts joined by FindPage
+var tagSortColumns = map[string]clause.Expr{
+	repository.SortMostTagged.Name: {SQL: "coalesce(counts.\"articleCount\", 0)"},
+	repository.SortTagSlug.Name:    {SQL: "tags.slug COLLATE \"C\""},
+}
+
+func (r *TagRepository) FindPage(filter repository.TagFilter, sort repository.TagSortOrder,
+	page repository.PageRequest) (repository.TagPage, error) {
+	column, ok := tagSortColumns[sort.Name]
+	if !ok {
+		return repository.TagPage{}, fmt.Errorf("unsupported sort %q", sort.Name)
+	}
+
+	base := func() *gorm.DB {
+		query := r.db.Model(&models.Tag{})
+		if filter.Search != "" {
+			pattern := "%" + escapeLike(filter.Search) + "%"
+			query = query.Where("tags.slug ILIKE ? OR tags.name ILIKE ?", pattern, pattern)
+		}
+		return query
+	}
+
+	var total int64
+	if err := base().Count(&total).Error; err != nil {
+		return repository.TagPage{}, err
+	}
+
+	query := base().
+		Select("tags.*, coalesce(counts.\"articleCount\", 0) AS \"articleCount\"").
+		Joins("LEFT JOIN (SELECT \"tagID\", count(*) AS \"articleCount\" FROM article_tags GROUP BY \"tagID\") counts" +
+			" ON counts.\"tagID\" = tags.id")
+	query, err := applyKeyset(query, column, "tags.id", sort, page)
+	if err != nil {
+		return repository.TagPage{}, err
+	}
+
+	tags := []models.Tag{}
+	if err := query.Find(&tags).Error; err != nil {
+		return repository.TagPage{}, err
+	}
+
+	return repository.NewTagPage(tags, total, sort, page), nil
+}
+
+func (r *TagRepository) FindByArticle(articleID string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	err := r.db.
+		Select("tags.*").
+		Joins("JOIN article_tags ON article_tags.\"tagID\" = tags.id").
+		Where("article_tags.\"articleID\" = ?", articleID).
+		Order("tags.slug").
+		Find(&tags).Error
+	if err != nil {
+		return tags, translateError("tag", err)
+	}
+
+	return tags, nil
+}
+
+// SetArticleTags replaces the tags of the article
+func (r *TagRepository) SetArticleTags(articleID string, tagIDs []string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("\"articleID\" = ?", articleID).Delete(&models.ArticleTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+
+		articleTags := make([]models.ArticleTag, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			articleTags = append(articleTags, models.ArticleTag{ArticleID: articleID, TagID: tagID})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&articleTags).Error
+	})
+	if err != nil {
+		return translateError("article tags", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// timeseriesGroups holds the key, label and FROM clause of every
+// timeseries grouping
+var timeseriesGroups = map[string]struct {
+	key, label, from string
+}{
+	"": {key: "'all'", label: "'All articles'", from: "articles"},
+	repository.GroupByAuthor: {
+		key:   "articles.\"authorID\"",
+		label: "authors.name",
+		from:  "articles JOIN authors ON authors.id = articles.\"authorID\"",
+	},
+	repository.GroupByTag: {
+		key:   "tags.slug",
+		label: "tags.name",
+		from: "articles JOIN article_tags ON article_tags.\"articleID\" = articles.id" +
+			" JOIN tags ON tags.id = article_tags.\"tagID\"",
+	},
+}
+
+// FindTimeseries buckets postedAt with date_trunc in the requested
+// zone and zero fills the buckets with generate_series
+func (r *ArticleRepository) FindTimeseries(options repository.TimeseriesOptions) ([]repository.TimeseriesSeries, error) {
+	group, ok := timeseriesGroups[options.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported timeseries grouping %q", options.GroupBy)
+	}
+
+	bucket := "date_trunc(@interval, articles.\"postedAt\" AT TIME ZONE @tz)::date"
+	inRange := "articles.\"postedAt\" AT TIME ZONE @tz >= date_trunc(@interval, CAST(@from AS timestamp))" +
+		" AND articles.\"postedAt\" AT TIME ZONE @tz < date_trunc(@interval, CAST(@to AS timestamp)) + ('1 ' || @interval)::interval"
+
+	// Without grouping the single series exists even when no article
+	// is in range, GROUP BY would return no row
+	groupBy, groupLimit := "GROUP BY 1, 2", options.GroupLimit
+	if options.GroupBy == "" {
+		groupBy, groupLimit = "", 1
+	}
+
+	query := fmt.Sprintf(`WITH groups AS (
+	SELECT %[1]s AS key, %[2]s AS label, count(*) AS total
+	FROM %[3]s
+	WHERE %[5]s
+	%[6]s
+	ORDER BY total DESC, key
+	LIMIT @groupLimit
+), buckets AS (
+	SELECT generate_series(
+		date_trunc(@interval, CAST(@from AS timestamp)),
+		date_trunc(@interval, CAST(@to AS timestamp)),
+		('1 ' || @interval)::interval
+	)::date AS bucket
+), counts AS (
+	SELECT %[1]s AS key, %[4]s AS bucket, count(*) AS count
+	FROM %[3]s
+	WHERE %[5]s
+	GROUP BY 1, 2
+)
+SELECT groups.key, groups.label, groups.total, buckets.bucket, coalesce(counts.count, 0) AS count
+FROM groups
+CROSS JOIN buckets
+LEFT JOIN counts ON counts.key = groups.key AND counts.bucket = buckets.bucket
+ORDER BY groups.total DESC, groups.key, buckets.bucket`, group.key, group.label, group.from, bucket, inRange, groupBy)
+
+	var rows []struct {
+		Key    string    `gorm:"column:key"`
+		Label  string    `gorm:"column:label"`
+		Total  int64     `gorm:"column:total"`
+		Bucket time.Time `gorm:"column:bucket"`
+		Count  int64     `gorm:"column:count"`
+	}
+	err := r.db.Raw(query, map[string]interface{}{
+		"interval":   options.Interval,
+		"tz":         options.Location.String(),
+		"from":       options.From.Format(time.DateOnly),
+		"to":         options.To.Format(time.DateOnly),
+		"groupLimit": groupLimit,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	series := []repository.TimeseriesSeries{}
+	for _, row := range rows {
+		if len(series) == 0 || series[len(series)-1].Key != row.Key {
+			series = append(series, repository.TimeseriesSeries{Key: row.Key, Label: row.Label, Total: row.Total})
+		}
+		current := &series[len(series)-1]
+		current.Points = append(current.Points, repository.TimeseriesPoint{
+			Bucket: row.Bucket.Format(time.DateOnly),
+			Count:  row.Count,
+		})
+	}
+
+	return series, nil
+}
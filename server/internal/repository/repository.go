@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+)
+
+// ArticleRepository persists articles. Methods that return articles
+// for display also load each article's Author
+type ArticleRepository interface {
+	Create(article models.Article) (models.Article, error)
+	FindOne(id string) (models.Article, error)
+	FindByTitle(title string) (models.Article, error)
+	FindAll(limit float64, cursor string) ([]models.Article, int64, error)
+	FindPage(filter ArticleFilter, sort SortOrder, page PageRequest) (ArticlePage, error)
+	// Stream hands every article matching the filter to handle, newest
+	// first, in batches of batchSize
+	Stream(filter ArticleFilter, batchSize int, handle func(articles []models.Article) error) error
+	FindAllByPostedAtInAsc(limit int) ([]models.Article, int64, error)
+	FindAllWithWrongImage(limit float64, cursor string) ([]models.Article, int64, error)
+	// FindAllWithoutImageMetadata pages by id through the articles with
+	// an image but no metadata, skipping those marked as failed
+	FindAllWithoutImageMetadata(afterID string, limit int) ([]models.Article, error)
+	MarkImageMetadataFailed(id string) error
+	FindCount() (int64, error)
+	// Search orders by relevance and fills SearchRank and SearchHighlight
+	Search(options SearchOptions) (ArticlePage, error)
+	// DidYouMean returns the title closest to a query that found nothing
+	DidYouMean(searchQuery string, threshold float64) (string, error)
+	SearchByTagIndex(searchQuery string) ([]models.Article, int64, error)
+	FindByTagIndex(tagIndex string) (models.Article, error)
+	FindNeighbours(article models.Article) (ArticleNeighbours, error)
+	// IncrementClickCount is the only method that changes ClickCount
+	IncrementClickCount(id string) error
+	// FindByPostedAt lists the articles of the calendar day of date in
+	// location, taking its year, month and day as written
+	FindByPostedAt(date time.Time, location *time.Location) ([]models.Article, error)
+	// FindArticleCountPerDay counts the articles of each calendar day in
+	// location, from the day of dateCursor back. The counts may come
+	// from a rollup kept in sync with the articles
+	FindArticleCountPerDay(limit int, dateCursor time.Time, location *time.Location) ([]map[string]interface{}, error)
+	CountDistinctDays(count *int64, location *time.Location) error
+	// RebuildDayCounts adds zones to the day count rollup and recounts
+	// it from scratch. Only UTC and the zones added here are rolled up
+	RebuildDayCounts(zones []string) error
+	// FindAuthorDayCounts lists the articles of every author per day,
+	// oldest day first
+	FindAuthorDayCounts(location *time.Location) ([]AuthorDayCount, error)
+	FindTimeseries(options TimeseriesOptions) ([]TimeseriesSeries, error)
+	Update(article models.Article) (models.Article, error)
+	Delete(id string) error
+}
+
+// ArticleNeighbours are the articles posted right before and right
+// after an article, ties broken by id like the keyset listings
+type ArticleNeighbours struct {
+	Previous *models.Article `json:"previous"`
+	Next     *models.Article `json:"next"`
+}
+
+// AuthorRepository persists authors. FindPage fills ArticleCount and
+// LatestPostedAt of the listed authors
+type AuthorRepository interface {
+	Create(author models.Author) (models.Author, error)
+	FindOne(id string) (models.Author, error)
+	FindByName(name string) (models.Author, error)
+	FindByPage(pageURL string) (models.Author, error)
+	FindAll(limit float64, cursor string) ([]models.Author, error)
+	FindPage(filter AuthorFilter, sort AuthorSortOrder, page PageRequest) (AuthorPage, error)
+	FindStats(id string) (AuthorStats, error)
+	Update(author models.Author) (models.Author, error)
+	Delete(id string) error
+}
+
+// TagRepository persists normalized tags and the tags of articles.
+// Tags are looked up by slug or by any of their aliases, the other
+// spellings FindOrCreate was given for the slug. FindPage fills the
+// ArticleCount of the listed tags
+type TagRepository interface {
+	FindOrCreate(name string) (models.Tag, error)
+	FindBySlug(slug string) (models.Tag, error)
+	FindPage(filter TagFilter, sort TagSortOrder, page PageRequest) (TagPage, error)
+	FindByArticle(articleID string) ([]models.Tag, error)
+	SetArticleTags(articleID string, tagIDs []string) error
+}
+
+type FileRecordRepository interface {
+	Create(fileRecord models.FileRecord) (models.FileRecord, error)
+	FindByFilename(filename string) (models.FileRecord, error)
+	Delete(id string) error
+}
+
+// PendingUploadRepository tracks presigned uploads until they are
+// completed. FindExpired lists uploads that expired before the given
+// time, oldest first
+type PendingUploadRepository interface {
+	Create(pendingUpload models.PendingUpload) (models.PendingUpload, error)
+	FindByKey(key string) (models.PendingUpload, error)
+	FindExpired(before time.Time, limit int) ([]models.PendingUpload, error)
+	Delete(key string) error
+}
+
+// SnapshotRepository copies every row out and back in with its id,
+// click count and timestamps kept. Dump reads all tables from one
+// consistent view and hands them to handle in batches of batchSize,
+// table by table in the order Restore needs: authors, articles, tags,
+// article tags, then file records. Restore upserts the rows by id so
+// restoring a batch again changes nothing
+type SnapshotRepository interface {
+	Dump(batchSize int, handle func(batch SnapshotBatch) error) error
+	Restore(batch SnapshotBatch) error
+}
+
+// Repositories bundles every repository a handler may need so
+// backends can be swapped in one place
+type Repositories struct {
+	Articles       ArticleRepository
+	Authors        AuthorRepository
+	Tags           TagRepository
+	FileRecords    FileRecordRepository
+	PendingUploads PendingUploadRepository
+	Snapshots      SnapshotRepository
+}
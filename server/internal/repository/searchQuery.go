@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SearchOptions configures ArticleRepository.Search. With Fuzzy set
+// titles whose trigram similarity to Query reaches FuzzyThreshold
+// match too, and the similarity is added to the full-text rank.
+// Results page with SortRelevance cursors
+type SearchOptions struct {
+	Query          string
+	Fuzzy          bool
+	FuzzyThreshold float64
+	Page           PageRequest
+}
+
+// SearchTerm is one word or quoted phrase of a search query. Prefix
+// is set when the term ends with "*", e.g. bitco* or "lightning net*"
+type SearchTerm struct {
+	Words  []string
+	Prefix bool
+}
+
+func (t SearchTerm) IsPhrase() bool {
+	return len(t.Words) > 1
+}
+
+type SearchQuery []SearchTerm
+
+// ParseSearchQuery splits the user's query into terms. Text between
+// double quotes is a phrase, everything else is matched word by word.
+// Punctuation is dropped so the result is always a valid tsquery
+func ParseSearchQuery(searchQuery string) SearchQuery {
+	var terms SearchQuery
+
+	for index, part := range strings.Split(searchQuery, "\"") {
+		// Odd parts sit between a pair of quotes
+		if index%2 == 1 {
+			if term, ok := parseSearchTerm(part); ok {
+				terms = append(terms, term)
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			if term, ok := parseSearchTerm(field); ok {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	return terms
+}
+
+func parseSearchTerm(text string) (SearchTerm, bool) {
+	text = strings.TrimSpace(text)
+	term := SearchTerm{Prefix: strings.HasSuffix(text, "*")}
+
+	term.Words = SearchWords(text)
+
+	return term, len(term.Words) > 0
+}
+
+// SearchWords lowercases text and splits it on anything that isn't a
+// letter or a digit
+func SearchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// TSQuery renders the query for Postgres' to_tsquery: terms are
+// ANDed, phrase words are joined with <-> and prefixes get :*
+func (q SearchQuery) TSQuery() string {
+	var terms []string
+
+	for _, term := range q {
+		words := append([]string{}, term.Words...)
+		if term.Prefix {
+			words[len(words)-1] += ":*"
+		}
+		if term.IsPhrase() {
+			terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+			continue
+		}
+		terms = append(terms, words[0])
+	}
+
+	return strings.Join(terms, " & ")
+}
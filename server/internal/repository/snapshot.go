@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+
+// SnapshotBatch holds rows of one table, the other slices are empty
+type SnapshotBatch struct {
+	Authors     []models.Author
+	Articles    []models.Article
+	Tags        []models.Tag
+	ArticleTags []models.ArticleTag
+	FileRecords []models.FileRecord
+}
+
+// Len is the number of rows in the batch
+func (b SnapshotBatch) Len() int {
+	return len(b.Authors) + len(b.Articles) + len(b.Tags) + len(b.ArticleTags) + len(b.FileRecords)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"strconv"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+)
+
+// TagSortOrder orders tag listings. Listed tags carry their
+// ArticleCount
+type TagSortOrder = Sort[models.Tag]
+
+var SortMostTagged = TagSortOrder{
+	Name: "articles",
+	Desc: true,
+	Key: func(tag models.Tag) string {
+		return strconv.FormatInt(tag.ArticleCount, 10)
+	},
+	ParseKey: parseIntKey,
+}
+
+var SortTagSlug = TagSortOrder{
+	Name: "slug",
+	Key: func(tag models.Tag) string {
+		return tag.Slug
+	},
+	ParseKey: parseStringKey,
+}
+
+// TagSorts are the sort orders tag listings accept
+var TagSorts = map[string]TagSortOrder{
+	SortMostTagged.Name: SortMostTagged,
+	SortTagSlug.Name:    SortTagSlug,
+}
+
+// TagFilter narrows tag listings, Search matches part of the slug or
+// name case-insensitively
+type TagFilter struct {
+	Search string
+}
+
+type TagPage struct {
+	Tags []models.Tag
+	PageInfo
+}
+
+func tagID(tag models.Tag) string { return tag.ID }
+
+// NewTagPage builds the page from the tags fetched for it, see NewPage
+func NewTagPage(tags []models.Tag, total int64, sort TagSortOrder, page PageRequest) TagPage {
+	tags, info := NewPage(tags, total, tagID, sort, page)
+	return TagPage{Tags: tags, PageInfo: info}
+}
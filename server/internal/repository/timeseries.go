@@ -0,0 +1,83 @@
+package repository
+
+import "time"
+
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+	IntervalYear  = "year"
+)
+
+var Intervals = []string{IntervalDay, IntervalWeek, IntervalMonth, IntervalYear}
+
+const (
+	GroupByTag    = "tag"
+	GroupByAuthor = "author"
+)
+
+// TimeseriesOptions select the buckets of a timeseries. From and To
+// are calendar days in Location, buckets cover both. Grouped series
+// keep the GroupLimit groups with the most articles in the range
+type TimeseriesOptions struct {
+	Interval   string
+	From       time.Time
+	To         time.Time
+	Location   *time.Location
+	GroupBy    string
+	GroupLimit int
+}
+
+type TimeseriesPoint struct {
+	Bucket string `json:"bucket"`
+	Count  int64  `json:"count"`
+}
+
+// TimeseriesSeries holds a count for every bucket, zero filled. Key
+// is a tag slug or author id, or "all" for an ungrouped series
+type TimeseriesSeries struct {
+	Key    string            `json:"key"`
+	Label  string            `json:"label"`
+	Total  int64             `json:"total"`
+	Points []TimeseriesPoint `json:"points"`
+}
+
+// TruncateDate returns the start of the interval holding the calendar
+// day of t, like date_trunc. Weeks start on Monday
+func TruncateDate(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch interval {
+	case IntervalWeek:
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -daysSinceMonday)
+	case IntervalMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case IntervalYear:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// NextBucket returns the start of the interval following bucket
+func NextBucket(bucket time.Time, interval string) time.Time {
+	switch interval {
+	case IntervalWeek:
+		return bucket.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return bucket.AddDate(0, 1, 0)
+	case IntervalYear:
+		return bucket.AddDate(1, 0, 0)
+	}
+	return bucket.AddDate(0, 0, 1)
+}
+
+// Buckets lists the starts of the intervals covering from to to
+func Buckets(from, to time.Time, interval string) []time.Time {
+	var buckets []time.Time
+	last := TruncateDate(to, interval)
+	for bucket := TruncateDate(from, interval); !bucket.After(last); bucket = NextBucket(bucket, interval) {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
@@ -0,0 +1,382 @@
+// Package snapshot writes and restores versioned archives of the
+// index. An archive is a gzipped tar of manifest.json followed by one
+// NDJSON entry per batch of rows, in the order the rows restore.
+// Archives made with blobs also hold the bucket objects the rows point
+// at, each written right before the first batch that needs it
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/constants"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/models"
+	"github.com/Tibz-Dankan/hackernoon-articles/internal/repository"
+)
+
+// Version of the archive layout, bumped when it changes in a way
+// older code can't read
+const Version = 1
+
+const (
+	manifestEntry = "manifest.json"
+	tablesDir     = "tables/"
+	blobsDir      = "blobs/"
+)
+
+const (
+	tableAuthors     = "authors"
+	tableArticles    = "articles"
+	tableTags        = "tags"
+	tableArticleTags = "article_tags"
+	tableFileRecords = "file_records"
+)
+
+type Manifest struct {
+	Version int `json:"version"`
+	// SchemaVersion is the latest migration of the dumped database,
+	// archives restore into a schema at least that recent
+	SchemaVersion int       `json:"schemaVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Blobs         bool      `json:"blobs"`
+}
+
+// BlobStore is the bucket blobs are read from and restored to, it is
+// implemented by pkg.S3Client
+type BlobStore interface {
+	DownloadFile(ctx context.Context, filename string, maxBytes int64) ([]byte, error)
+	FileExists(ctx context.Context, filename string) (bool, error)
+	PutFile(ctx context.Context, file io.Reader, filename string, contentType string) error
+	ObjectURL(filename string) string
+}
+
+// Summary counts the rows and blobs of an archive. MissingBlobs are
+// files the rows name that the bucket doesn't have, ExistingBlobs
+// were already in the bucket on restore
+type Summary struct {
+	Authors       int `json:"authors"`
+	Articles      int `json:"articles"`
+	Tags          int `json:"tags"`
+	ArticleTags   int `json:"articleTags"`
+	FileRecords   int `json:"fileRecords"`
+	Blobs         int `json:"blobs"`
+	MissingBlobs  int `json:"missingBlobs"`
+	ExistingBlobs int `json:"existingBlobs"`
+}
+
+func (s *Summary) add(batch repository.SnapshotBatch) {
+	s.Authors += len(batch.Authors)
+	s.Articles += len(batch.Articles)
+	s.Tags += len(batch.Tags)
+	s.ArticleTags += len(batch.ArticleTags)
+	s.FileRecords += len(batch.FileRecords)
+}
+
+// Create dumps the repository into an archive written to output.
+// Blobs are downloaded from the store unless it is nil
+func Create(ctx context.Context, output io.Writer, snapshots repository.SnapshotRepository,
+	blobs BlobStore, schemaVersion int) (Summary, error) {
+	var summary Summary
+
+	gzipWriter := gzip.NewWriter(output)
+	archive := tar.NewWriter(gzipWriter)
+
+	manifest := Manifest{
+		Version:       Version,
+		SchemaVersion: schemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Blobs:         blobs != nil,
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return summary, err
+	}
+	if err := writeEntry(archive, manifestEntry, manifestData, manifest.CreatedAt); err != nil {
+		return summary, err
+	}
+
+	written := make(map[string]bool)
+	var sequence int
+
+	err = snapshots.Dump(constants.SNAPSHOT_BATCH_SIZE, func(batch repository.SnapshotBatch) error {
+		if blobs != nil {
+			for _, filename := range blobFilenames(batch) {
+				if written[filename] {
+					continue
+				}
+				written[filename] = true
+
+				data, err := blobs.DownloadFile(ctx, filename, constants.SNAPSHOT_BLOB_MAX_BYTES)
+				if err != nil {
+					log.Printf("Skipping blob %s: %v", filename, err)
+					summary.MissingBlobs++
+					continue
+				}
+				if err := writeEntry(archive, blobsDir+filename, data, manifest.CreatedAt); err != nil {
+					return err
+				}
+				summary.Blobs++
+			}
+		}
+
+		table, rows := batchRows(batch)
+		var buffer bytes.Buffer
+		encoder := json.NewEncoder(&buffer)
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+
+		sequence++
+		name := fmt.Sprintf("%s%06d-%s.ndjson", tablesDir, sequence, table)
+		if err := writeEntry(archive, name, buffer.Bytes(), manifest.CreatedAt); err != nil {
+			return err
+		}
+		summary.add(batch)
+		return nil
+	})
+	if err != nil {
+		return summary, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return summary, err
+	}
+	return summary, gzipWriter.Close()
+}
+
+func writeEntry(archive *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := archive.Write(data)
+	return err
+}
+
+// blobFilenames are the bucket objects the rows of the batch name
+func blobFilenames(batch repository.SnapshotBatch) []string {
+	var filenames []string
+	for _, author := range batch.Authors {
+		filenames = append(filenames, author.AvatarFilename)
+	}
+	for _, article := range batch.Articles {
+		filenames = append(filenames, article.ImageFilename)
+	}
+	for _, fileRecord := range batch.FileRecords {
+		filenames = append(filenames, fileRecord.Filename)
+	}
+
+	named := filenames[:0]
+	for _, filename := range filenames {
+		if filename != "" {
+			named = append(named, filename)
+		}
+	}
+	return named
+}
+
+func batchRows(batch repository.SnapshotBatch) (string, []interface{}) {
+	var rows []interface{}
+	switch {
+	case len(batch.Authors) > 0:
+		for _, author := range batch.Authors {
+			rows = append(rows, author)
+		}
+		return tableAuthors, rows
+	case len(batch.Articles) > 0:
+		for _, article := range batch.Articles {
+			rows = append(rows, article)
+		}
+		return tableArticles, rows
+	case len(batch.Tags) > 0:
+		for _, tag := range batch.Tags {
+			rows = append(rows, tag)
+		}
+		return tableTags, rows
+	case len(batch.ArticleTags) > 0:
+		for _, articleTag := range batch.ArticleTags {
+			rows = append(rows, articleTag)
+		}
+		return tableArticleTags, rows
+	default:
+		for _, fileRecord := range batch.FileRecords {
+			rows = append(rows, fileRecord)
+		}
+		return tableFileRecords, rows
+	}
+}
+
+// Restore reads an archive into the repository, batch by batch.
+// Rows are upserted by id and blobs already in the bucket are left
+// alone, so a restore that failed halfway can simply run again. With
+// a blob store the archived blobs are uploaded to it and the urls of
+// the rows are pointed at it, without one blobs are skipped and the
+// urls kept
+func Restore(ctx context.Context, input io.Reader, snapshots repository.SnapshotRepository,
+	blobs BlobStore, schemaVersion int) (Manifest, Summary, error) {
+	var manifest Manifest
+	var summary Summary
+
+	gzipReader, err := gzip.NewReader(bufio.NewReader(input))
+	if err != nil {
+		return manifest, summary, fmt.Errorf("reading the archive: %w", err)
+	}
+	defer gzipReader.Close()
+	archive := tar.NewReader(gzipReader)
+
+	header, err := archive.Next()
+	if err != nil {
+		return manifest, summary, fmt.Errorf("reading the archive: %w", err)
+	}
+	if header.Name != manifestEntry {
+		return manifest, summary, fmt.Errorf("archive starts with %s, want %s", header.Name, manifestEntry)
+	}
+	if err := json.NewDecoder(archive).Decode(&manifest); err != nil {
+		return manifest, summary, fmt.Errorf("reading the manifest: %w", err)
+	}
+	if manifest.Version != Version {
+		return manifest, summary, fmt.Errorf("unsupported snapshot version %d, want %d", manifest.Version, Version)
+	}
+	if manifest.SchemaVersion > schemaVersion {
+		return manifest, summary, fmt.Errorf("snapshot needs schema version %d, the database is at %d",
+			manifest.SchemaVersion, schemaVersion)
+	}
+
+	// Blobs restored to the store, their rows are pointed at it
+	restored := make(map[string]bool)
+
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return manifest, summary, nil
+		}
+		if err != nil {
+			return manifest, summary, fmt.Errorf("reading the archive: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(header.Name, blobsDir):
+			if blobs == nil {
+				continue
+			}
+			filename := strings.TrimPrefix(header.Name, blobsDir)
+			if filename == "" || path.Clean(filename) != filename || strings.HasPrefix(filename, "../") {
+				return manifest, summary, fmt.Errorf("invalid blob name %q", header.Name)
+			}
+			exists, err := restoreBlob(ctx, blobs, archive, filename)
+			if err != nil {
+				return manifest, summary, fmt.Errorf("restoring blob %s: %w", filename, err)
+			}
+			if exists {
+				summary.ExistingBlobs++
+			} else {
+				summary.Blobs++
+			}
+			restored[filename] = true
+		case strings.HasPrefix(header.Name, tablesDir):
+			batch, err := readBatch(header.Name, archive)
+			if err != nil {
+				return manifest, summary, fmt.Errorf("reading %s: %w", header.Name, err)
+			}
+			if blobs != nil {
+				pointAtStore(&batch, blobs, restored)
+			}
+			if err := snapshots.Restore(batch); err != nil {
+				return manifest, summary, fmt.Errorf("restoring %s: %w", header.Name, err)
+			}
+			summary.add(batch)
+		default:
+			return manifest, summary, fmt.Errorf("unexpected archive entry %s", header.Name)
+		}
+	}
+}
+
+func restoreBlob(ctx context.Context, blobs BlobStore, data io.Reader, filename string) (bool, error) {
+	exists, err := blobs.FileExists(ctx, filename)
+	if err != nil || exists {
+		return exists, err
+	}
+
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return false, err
+	}
+	return false, blobs.PutFile(ctx, bytes.NewReader(content), filename, http.DetectContentType(content))
+}
+
+func readBatch(name string, data io.Reader) (repository.SnapshotBatch, error) {
+	var batch repository.SnapshotBatch
+
+	table := strings.TrimSuffix(name[strings.Index(name, "-")+1:], ".ndjson")
+	decoder := json.NewDecoder(data)
+	for decoder.More() {
+		var err error
+		switch table {
+		case tableAuthors:
+			var author models.Author
+			err = decoder.Decode(&author)
+			batch.Authors = append(batch.Authors, author)
+		case tableArticles:
+			var article models.Article
+			err = decoder.Decode(&article)
+			batch.Articles = append(batch.Articles, article)
+		case tableTags:
+			var tag models.Tag
+			err = decoder.Decode(&tag)
+			batch.Tags = append(batch.Tags, tag)
+		case tableArticleTags:
+			var articleTag models.ArticleTag
+			err = decoder.Decode(&articleTag)
+			batch.ArticleTags = append(batch.ArticleTags, articleTag)
+		case tableFileRecords:
+			var fileRecord models.FileRecord
+			err = decoder.Decode(&fileRecord)
+			batch.FileRecords = append(batch.FileRecords, fileRecord)
+		default:
+			return batch, fmt.Errorf("unknown table %q", table)
+		}
+		if err != nil {
+			return batch, err
+		}
+	}
+	return batch, nil
+}
+
+// pointAtStore rewrites the urls of rows whose blob was restored, the
+// store may be another bucket than the one the rows were dumped from
+func pointAtStore(batch *repository.SnapshotBatch, blobs BlobStore, restored map[string]bool) {
+	for i, author := range batch.Authors {
+		if restored[author.AvatarFilename] {
+			batch.Authors[i].AvatarUrl = blobs.ObjectURL(author.AvatarFilename)
+		}
+	}
+	for i, article := range batch.Articles {
+		if restored[article.ImageFilename] {
+			batch.Articles[i].ImageUrl = blobs.ObjectURL(article.ImageFilename)
+		}
+	}
+	for i, fileRecord := range batch.FileRecords {
+		if restored[fileRecord.Filename] {
+			batch.FileRecords[i].URL = blobs.ObjectURL(fileRecord.Filename)
+		}
+	}
+}